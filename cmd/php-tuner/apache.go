@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+)
+
+func runApache(args []string) {
+	fs := flag.NewFlagSet("apache", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		noColor        bool
+		onlyConf       bool
+		traffic        trafficOptions
+		reservedMemory int
+		processMemory  float64
+		noServices     bool
+		explain        bool
+		explainFormat  string
+		source         systemSource
+		tmplOpts       templateOptions
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.BoolVar(&onlyConf, "config-only", false, "")
+	fs.BoolVar(&onlyConf, "c", false, "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+	fs.BoolVar(&explain, "explain", false, "")
+	fs.StringVar(&explainFormat, "explain-format", "text", "")
+	source.register(fs)
+	tmplOpts.register(fs)
+
+	fs.Usage = func() { printApacheUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printApacheUsage()
+		return
+	}
+
+	profile := traffic.resolve()
+	tmplOpts.load()
+
+	explainJSON := explainFormatJSON(explainFormat) && explain
+
+	// JSON explanations and templates replace the regular report
+	var out io.Writer = os.Stdout
+	if explainJSON || tmplOpts.wanted() {
+		out = io.Discard
+	}
+
+	printer := output.NewPrinter(out, noColor, onlyConf)
+	printer.PrintApacheHeader()
+
+	env, err := source.load(apacheProcesses, !noServices && reservedMemory == 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error detecting system info: %v\n", err)
+		os.Exit(1)
+	}
+	printer.PrintSystemInfo(env.System)
+	printer.PrintApacheInfo(env.PHP)
+
+	opts := calculator.DefaultApacheOptions()
+	opts.ReservedMemoryMB = reservedMemory
+	opts.ProcessMemoryMB = processMemory
+	opts.TrafficProfile = profile
+
+	// Apache is the PHP runtime here, not a service competing with it
+	env.Services = slices.DeleteFunc(env.Services, func(svc services.Service) bool {
+		return svc.Name == "Apache"
+	})
+
+	cfg := calculator.CalculateApache(*env, opts)
+	mustValidate(cfg.Validate())
+
+	if explainJSON {
+		if err := output.NewPrinter(os.Stdout, noColor, false).PrintExplanationJSON("apache", cfg.Trace); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing explanation: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if tmplOpts.wanted() {
+		tmplOpts.render("apache", *env, cfg, cfg.Directives(), nil, nil, cfg.Warnings, cfg.Recommendations, cfg.Trace)
+		return
+	}
+
+	printer.PrintApacheCalculation(cfg)
+	if explain {
+		printer.PrintExplanation(cfg.Trace)
+	}
+	printer.PrintApacheConfig(cfg)
+	printer.PrintApacheWarnings(cfg)
+	printer.PrintApacheRecommendations(cfg)
+	printer.PrintApacheUsage()
+}
+
+func printApacheUsage() {
+	fmt.Println(`Apache Prefork Optimizer (mod_php)
+
+Sizes the prefork MPM from the memory of the apache2/httpd children, each
+of which embeds a PHP interpreter.
+
+USAGE:
+    php-tuner apache [options]
+
+OPTIONS:
+    -h, --help          Show help
+    -c, --config-only   Output only configuration
+    --no-color          Disable colors
+    --traffic <name>    low, medium, high, bursty, api, batch, cron (default: medium)
+    --profiles <file>   JSON file with custom traffic profiles
+    --reserved <MB>     Reserved memory for OS/services
+    --process-mem <MB>  Override Apache child memory
+    --no-services       Do not reserve memory for detected services
+                        (MySQL, PostgreSQL, Redis, Elasticsearch, ...)
+    --explain           Show every decision behind the configuration
+    --explain-format <f>  text or json (default: text)
+    --template <file>   Render the result through a Go text/template
+
+    --simulate-cpu <n>  Simulate a machine with n CPU cores (default: 2)
+    --simulate-mem <s>  Simulate a machine with this memory, e.g. 8G (default: 4G)
+    --system-from <f>   Read system information from a JSON file
+    --from-snapshot <f> Read system information from a snapshot
+
+EXAMPLES:
+    php-tuner apache
+    php-tuner apache --traffic high
+    php-tuner apache -c > /etc/apache2/mods-available/mpm_prefork.conf`)
+}
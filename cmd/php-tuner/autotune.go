@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/autotune"
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+func runAutotune(args []string) {
+	fs := flag.NewFlagSet("autotune", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		noColor        bool
+		history        bool
+		runtime        string
+		reload         string
+		traffic        trafficOptions
+		pmType         string
+		reservedMemory int
+		processMemory  float64
+		threadMemory   float64
+		noServices     bool
+		cfg            autotune.Config
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.BoolVar(&history, "history", false, "")
+	fs.StringVar(&runtime, "runtime", autotune.RuntimeFPM, "")
+	fs.StringVar(&cfg.ConfigPath, "current", "", "")
+	fs.StringVar(&cfg.Pool, "pool", "", "")
+	fs.StringVar(&cfg.StatePath, "state", "/var/lib/php-tuner/autotune.json", "")
+	fs.StringVar(&reload, "reload", "", "")
+	fs.StringVar(&cfg.StatusURL, "status-url", "", "")
+	fs.StringVar(&cfg.PrometheusURL, "prometheus", "", "")
+	fs.StringVar(&cfg.SaturationQuery, "saturation-query", "", "")
+	fs.StringVar(&cfg.LatencyQuery, "latency-query", "", "")
+	fs.IntVar(&cfg.Step, "step", 2, "")
+	fs.IntVar(&cfg.Min, "min", 2, "")
+	fs.IntVar(&cfg.Max, "max", 0, "")
+	fs.Float64Var(&cfg.HighSaturation, "high", 85, "")
+	fs.Float64Var(&cfg.LowSaturation, "low", 40, "")
+	fs.Float64Var(&cfg.MaxLatencyMS, "max-latency", 0, "")
+	fs.IntVar(&cfg.MaxListenQueue, "max-queue", 0, "")
+	fs.IntVar(&cfg.MaxSteps, "max-steps", 0, "")
+	fs.DurationVar(&cfg.Soak, "soak", 10*time.Minute, "")
+	fs.DurationVar(&cfg.SampleInterval, "interval", 15*time.Second, "")
+	fs.StringVar(&pmType, "pm", "", "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.Float64Var(&threadMemory, "thread-mem", 0, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+
+	fs.Usage = func() { printAutotuneUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printAutotuneUsage()
+		return
+	}
+
+	profile := traffic.resolve()
+	pm := parsePMType(pmType)
+
+	if history {
+		state, err := autotune.LoadState(cfg.StatePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		output.NewPrinter(os.Stdout, noColor, false).PrintAutotuneHistory(state)
+		return
+	}
+
+	if !noServices && reservedMemory == 0 {
+		cfg.Services = detectServices()
+	}
+
+	switch strings.ToLower(runtime) {
+	case "php-fpm", "fpm":
+		cfg.Runtime = autotune.RuntimeFPM
+		cfg.FPM = calculator.DefaultOptions()
+		cfg.FPM.ReservedMemoryMB = reservedMemory
+		cfg.FPM.ProcessMemoryMB = processMemory
+		cfg.FPM.TrafficProfile = profile
+		cfg.FPM.PMType = pm
+		if limit, err := php.GetPHPMemoryLimit(context.Background()); err == nil {
+			cfg.MemoryLimitMB = limit
+		}
+		cfg.ConfigPath = locateConfig(cfg.ConfigPath, deployed.FindFPMPool)
+		if reload == "" {
+			reload = "systemctl reload php-fpm"
+		}
+	case "frankenphp", "f":
+		cfg.Runtime = autotune.RuntimeFrankenPHP
+		cfg.FrankenPHP = calculator.DefaultFrankenPHPOptions()
+		cfg.FrankenPHP.ReservedMemoryMB = reservedMemory
+		cfg.FrankenPHP.ThreadMemoryMB = threadMemory
+		cfg.FrankenPHP.TrafficProfile = profile
+		cfg.ConfigPath = locateConfig(cfg.ConfigPath, deployed.FindCaddyfile)
+		if reload == "" {
+			reload = "frankenphp reload --config " + cfg.ConfigPath
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown runtime: %s (expected php-fpm or frankenphp)\n", runtime)
+		os.Exit(1)
+	}
+	cfg.Reload = strings.Fields(reload)
+
+	switch {
+	case cfg.StatusURL == "" && cfg.SaturationQuery == "":
+		fmt.Fprintln(os.Stderr, "Error: --status-url or --saturation-query is required to observe saturation")
+		os.Exit(1)
+	case (cfg.SaturationQuery != "" || cfg.LatencyQuery != "") && cfg.PrometheusURL == "":
+		fmt.Fprintln(os.Stderr, "Error: --prometheus is required for Prometheus queries")
+		os.Exit(1)
+	case cfg.Step <= 0 || cfg.Min <= 0:
+		fmt.Fprintln(os.Stderr, "Error: --step and --min must be positive")
+		os.Exit(1)
+	case cfg.Soak <= 0 || cfg.SampleInterval <= 0:
+		fmt.Fprintln(os.Stderr, "Error: --soak and --interval must be positive")
+		os.Exit(1)
+	case cfg.LowSaturation >= cfg.HighSaturation:
+		fmt.Fprintln(os.Stderr, "Error: --low must be below --high")
+		os.Exit(1)
+	}
+
+	cfg.Log = os.Stdout
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := autotune.New(cfg).Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printAutotuneUsage() {
+	fmt.Println(`Auto-Tune
+
+Adjusts pm.max_children (PHP-FPM) or num_threads (FrankenPHP) in small
+steps. Each step is observed for a soak period, then kept or reverted
+depending on saturation, latency and the memory budget. Every step is
+recorded in a state file, so an interrupted run resumes where it stopped
+and every change can be audited with --history.
+
+    step up     busy workers reach --high, the listen queue is non-empty or
+                max children reached increases (up to the memory-safe maximum)
+    step down   busy workers stay below --low or PHP exceeds its memory budget
+    revert      the memory budget or latency SLO is broken, or a step down
+                causes queueing
+
+USAGE:
+    php-tuner autotune [options]
+
+OPTIONS:
+    -h, --help                Show help
+    --no-color                Disable colors (--history)
+    --history                 Print the recorded steps and exit
+    --runtime <name>          php-fpm or frankenphp (default: php-fpm)
+    --current <file>          Pool file or Caddyfile to rewrite (default: auto-detect)
+    --pool <name>             Pool section to tune (default: first pool)
+    --state <file>            State file (default: /var/lib/php-tuner/autotune.json)
+    --reload <command>        Reload command (default: systemctl reload php-fpm,
+                              frankenphp reload --config <file>)
+
+    --status-url <url>        PHP-FPM status page (pm.status_path)
+    --prometheus <url>        Prometheus server for the queries below
+    --saturation-query <q>    PromQL returning busy workers (overrides the status page)
+    --latency-query <q>       PromQL returning request latency in milliseconds
+
+    --step <n>                Workers added or removed per step (default: 2)
+    --min <n>                 Lowest setting, FrankenPHP keeps one thread beyond
+                              the worker threads (default: 2)
+    --max <n>                 Highest setting (default: memory-safe maximum)
+    --high <pct>              Busy workers that trigger a step up (default: 85)
+    --low <pct>               Busy workers that trigger a step down (default: 40)
+    --max-latency <ms>        Latency SLO, 0 disables (default: 0)
+    --max-queue <n>           Listen queue tolerated after a step down (default: 0)
+    --max-steps <n>           Stop after n changes, 0 runs until interrupted
+    --soak <duration>         Observation period per step (default: 10m)
+    --interval <duration>     Sampling interval during the soak (default: 15s)
+
+    --traffic, --profiles, --pm, --reserved, --process-mem, --thread-mem, --no-services
+                              Same as the php-fpm and frankenphp commands
+
+EXAMPLES:
+    php-tuner autotune --status-url http://127.0.0.1/fpm-status \
+        --reload "systemctl reload php8.3-fpm" --max-latency 300 \
+        --prometheus http://prometheus:9090 \
+        --latency-query 'histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket[5m])) by (le)) * 1000'
+    php-tuner autotune --runtime frankenphp --prometheus http://prometheus:9090 \
+        --saturation-query 'sum(frankenphp_busy_threads)'
+    php-tuner autotune --history`)
+}
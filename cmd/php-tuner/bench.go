@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/bench"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+)
+
+func runBench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+
+	var (
+		showHelp  bool
+		noColor   bool
+		ramp      string
+		scripts   string
+		memory    string
+		cfg       bench.Config
+		rampSteps []int
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.StringVar(&cfg.FastCGI, "fastcgi", "", "")
+	fs.StringVar(&cfg.URL, "url", "", "")
+	fs.StringVar(&cfg.DocumentRoot, "docroot", "/var/www/html/public", "")
+	fs.StringVar(&scripts, "script", "/index.php", "")
+	fs.StringVar(&ramp, "ramp", "1,2,4,8,16,32,64", "")
+	fs.DurationVar(&cfg.StepDuration, "step", 10*time.Second, "")
+	fs.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "")
+	fs.Float64Var(&cfg.PlateauGain, "plateau-gain", 5, "")
+	fs.Float64Var(&cfg.MaxErrorRate, "max-errors", 1, "")
+	fs.StringVar(&memory, "memory-match", "auto", "")
+
+	fs.Usage = func() { printBenchUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printBenchUsage()
+		return
+	}
+
+	if (cfg.FastCGI == "") == (cfg.URL == "") {
+		fmt.Fprintln(os.Stderr, "Error: exactly one of --fastcgi or --url is required")
+		os.Exit(1)
+	}
+
+	for _, level := range strings.Split(ramp, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(level))
+		if err != nil || n <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid concurrency %q in --ramp\n", level)
+			os.Exit(1)
+		}
+		rampSteps = append(rampSteps, n)
+	}
+	cfg.Ramp = rampSteps
+
+	for _, script := range strings.Split(scripts, ",") {
+		if script = strings.TrimSpace(script); script != "" {
+			cfg.Scripts = append(cfg.Scripts, script)
+		}
+	}
+
+	// The calculator flag to feed the plateau back into differs per runtime
+	target, plateauCmd := cfg.URL, "f"
+	if cfg.FastCGI != "" {
+		target, plateauCmd = "fastcgi://"+cfg.FastCGI, "fpm"
+	}
+
+	cfg.ProcessMatch = memory
+	if memory == "auto" {
+		cfg.ProcessMatch = "frankenphp"
+		if cfg.FastCGI != "" {
+			cfg.ProcessMatch = "php-fpm|php[0-9]"
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	printer := output.NewPrinter(os.Stdout, noColor, false)
+	printer.PrintBenchHeader(target)
+
+	result, err := bench.RunWithProgress(ctx, cfg, printer.PrintBenchStep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	printer.PrintBenchResult(result, plateauCmd)
+}
+
+func printBenchUsage() {
+	fmt.Println(`Load Test
+
+Ramps up concurrency against PHP-FPM (FastCGI) or FrankenPHP (HTTP),
+records latency percentiles, error rates and worker memory, and reports
+the concurrency at which throughput plateaus.
+
+USAGE:
+    php-tuner bench --fastcgi <addr> [options]
+    php-tuner bench --url <url> [options]
+
+OPTIONS:
+    -h, --help              Show help
+    --no-color              Disable colors
+    --fastcgi <addr>        PHP-FPM address: 127.0.0.1:9000 or unix:/run/php/php-fpm.sock
+    --url <url>             Base URL for HTTP targets (FrankenPHP)
+    --docroot <dir>         Document root for SCRIPT_FILENAME (default: /var/www/html/public)
+    --script <list>         Comma separated script paths (default: /index.php)
+    --ramp <list>           Concurrency levels (default: 1,2,4,8,16,32,64)
+    --step <duration>       Duration of each level (default: 10s)
+    --timeout <duration>    Request timeout (default: 30s)
+    --plateau-gain <pct>    Minimum throughput gain to keep ramping (default: 5)
+    --max-errors <pct>      Error rate that ends the ramp (default: 1)
+    --memory-match <regex>  Processes to sample for worker memory
+                            (default: auto, empty disables)
+
+EXAMPLES:
+    php-tuner bench --fastcgi unix:/run/php/php8.3-fpm.sock --docroot /srv/app/public
+    php-tuner bench --url http://localhost:8080 --script /,/api/health --ramp 4,8,16,32
+
+The measured plateau can be passed back with --plateau to cap
+pm.max_children or num_threads/max_threads.`)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+const defaultShapes = "1x1G,2x4G,4x8G,8x16G,16x32G"
+
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		noColor        bool
+		runtime        string
+		shapes         string
+		pmType         string
+		traffic        trafficOptions
+		reservedMemory int
+		processMemory  float64
+		threadMemory   float64
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.StringVar(&runtime, "runtime", "frankenphp", "")
+	fs.StringVar(&shapes, "shapes", defaultShapes, "")
+	fs.StringVar(&pmType, "pm", "", "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.Float64Var(&threadMemory, "thread-mem", 0, "")
+
+	fs.Usage = func() { printCompareUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printCompareUsage()
+		return
+	}
+
+	profile := traffic.resolve()
+	pm := parsePMType(pmType)
+
+	// Positional shapes take precedence over --shapes
+	list := fs.Args()
+	if len(list) == 0 {
+		list = strings.Split(shapes, ",")
+	}
+
+	var rows []output.Comparison
+	for _, shape := range list {
+		cpu, mem, err := system.ParseShape(shape)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		row := output.Comparison{Shape: strings.TrimSpace(shape), System: system.Simulate(cpu, mem)}
+
+		switch strings.ToLower(runtime) {
+		case "php-fpm", "fpm":
+			opts := calculator.DefaultOptions()
+			opts.ReservedMemoryMB = reservedMemory
+			opts.ProcessMemoryMB = processMemory
+			opts.TrafficProfile = profile
+			opts.PMType = pm
+			row.FPM = calculator.Calculate(calculator.Input{System: row.System}, opts)
+			mustValidate(row.FPM.Validate())
+		case "frankenphp", "f":
+			opts := calculator.DefaultFrankenPHPOptions()
+			opts.ReservedMemoryMB = reservedMemory
+			opts.ThreadMemoryMB = threadMemory
+			opts.TrafficProfile = profile
+			row.FrankenPHP = calculator.CalculateFrankenPHP(calculator.Input{System: row.System}, opts)
+			mustValidate(row.FrankenPHP.Validate())
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown runtime: %s (expected frankenphp or php-fpm)\n", runtime)
+			os.Exit(1)
+		}
+
+		rows = append(rows, row)
+	}
+
+	output.NewPrinter(os.Stdout, noColor, false).PrintComparison(rows)
+}
+
+func printCompareUsage() {
+	fmt.Println(`Machine Comparison
+
+Tabulates recommended configurations for hypothetical machine shapes
+without probing the current host.
+
+USAGE:
+    php-tuner compare [options] [shape...]
+
+SHAPES:
+    <cpu>x<memory>, e.g. 2x4G, 8x16G, 4x6144 (memory in MB without unit)
+
+OPTIONS:
+    -h, --help          Show help
+    --no-color          Disable colors
+    --runtime <name>    frankenphp or php-fpm (default: frankenphp)
+    --shapes <list>     Comma separated shapes (default: ` + defaultShapes + `)
+    --traffic <name>    low, medium, high, bursty, api, batch, cron (default: medium)
+    --profiles <file>   JSON file with custom traffic profiles
+    --pm <type>         static, dynamic, ondemand (php-fpm only)
+    --reserved <MB>     Reserved memory for OS/services
+    --process-mem <MB>  PHP-FPM process memory (default: 64MB estimate)
+    --thread-mem <MB>   FrankenPHP thread memory (default: 30MB estimate)
+
+EXAMPLES:
+    php-tuner compare
+    php-tuner compare --runtime fpm --process-mem 80 2x4G 4x8G 8x16G`)
+}
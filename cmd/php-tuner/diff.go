@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/diff"
+)
+
+// exitDrift is returned when the deployed config drifts beyond the threshold
+const exitDrift = 3
+
+// diffOptions compares the recommendation with the deployed config
+type diffOptions struct {
+	enabled   bool
+	current   string
+	threshold float64
+}
+
+func (d *diffOptions) register(fs *flag.FlagSet) {
+	fs.BoolVar(&d.enabled, "diff", false, "Compare with the deployed configuration")
+	fs.StringVar(&d.current, "current", "", "Deployed config file (default: auto-detect)")
+	fs.Float64Var(&d.threshold, "drift-threshold", 20, "Drift in percent that causes exit code 3")
+}
+
+// resolve returns the deployed config path, locating it when not given
+func (d *diffOptions) resolve(find func() (string, error)) string {
+	return locateConfig(d.current, find)
+}
+
+// locateConfig returns path when given, otherwise the first config file find
+// locates
+func locateConfig(path string, find func() (string, error)) string {
+	if path != "" {
+		return path
+	}
+
+	path, err := find()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v (use --current <file>)\n", err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// exitOnDrift exits with exitDrift when drift exceeds the threshold
+func (d *diffOptions) exitOnDrift(changes []diff.Change) {
+	if diff.MaxDrift(changes) > d.threshold {
+		os.Exit(exitDrift)
+	}
+}
+
+func loadFPMPool(d *diffOptions, pool string) (string, map[string]string) {
+	path := d.resolve(deployed.FindFPMPool)
+	current, err := deployed.ParseFPMPool(path, pool)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading pool config: %v\n", err)
+		os.Exit(1)
+	}
+	return path, current
+}
+
+func loadCaddyfile(d *diffOptions) (string, map[string]string) {
+	path := d.resolve(deployed.FindCaddyfile)
+	current, err := deployed.ParseCaddyfile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading Caddyfile: %v\n", err)
+		os.Exit(1)
+	}
+	return path, current
+}
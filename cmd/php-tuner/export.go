@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/export"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+)
+
+// exportOptions prints the values as variables for Ansible, Terraform, Helm
+// or a user-defined layout instead of a config file
+type exportOptions struct {
+	target  string
+	mapFile string
+	mapping *export.Mapping
+}
+
+func (e *exportOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&e.target, "export", "", "Export variables: ansible, terraform, helm")
+	fs.StringVar(&e.mapFile, "export-map", "", "JSON file mapping values to export keys")
+}
+
+// resolve loads the mapping, exiting on errors so they show before any
+// output
+func (e *exportOptions) resolve() {
+	var (
+		m   export.Mapping
+		err error
+	)
+	switch {
+	case e.target != "" && e.mapFile != "":
+		err = fmt.Errorf("--export and --export-map cannot be combined")
+	case e.mapFile != "":
+		m, err = export.LoadMapping(e.mapFile)
+	case e.target != "":
+		m, err = export.Lookup(e.target)
+	default:
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	e.mapping = &m
+}
+
+// wanted reports whether an export replaces the config output
+func (e *exportOptions) wanted() bool {
+	return e.mapping != nil
+}
+
+// print writes the config's directives and container limits in the
+// target's layout
+func (e *exportOptions) print(printer *output.Printer, directives []calculator.Directive, limits *calculator.SystemdConfig) {
+	if err := printer.PrintExport(*e.mapping, export.Values(directives, limits)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing export: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/exporter"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+func runExporter(args []string) {
+	fs := flag.NewFlagSet("exporter", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		listen         string
+		runtime        string
+		interval       time.Duration
+		current        string
+		pool           string
+		pmType         string
+		traffic        trafficOptions
+		reservedMemory int
+		processMemory  float64
+		threadMemory   float64
+		noServices     bool
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.StringVar(&listen, "listen", ":9253", "")
+	fs.StringVar(&runtime, "runtime", exporter.RuntimeFPM, "")
+	fs.DurationVar(&interval, "interval", 15*time.Second, "")
+	fs.StringVar(&current, "current", "", "")
+	fs.StringVar(&pool, "pool", "", "")
+	fs.StringVar(&pmType, "pm", "", "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.Float64Var(&threadMemory, "thread-mem", 0, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+
+	fs.Usage = func() { printExporterUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printExporterUsage()
+		return
+	}
+
+	profile := traffic.resolve()
+	pm := parsePMType(pmType)
+
+	if interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+		os.Exit(1)
+	}
+
+	cfg := exporter.Config{Interval: interval, Current: current, Pool: pool}
+
+	if !noServices && reservedMemory == 0 {
+		cfg.Services = detectServices()
+	}
+
+	switch strings.ToLower(runtime) {
+	case "php-fpm", "fpm":
+		cfg.Runtime = exporter.RuntimeFPM
+		cfg.FPM = calculator.DefaultOptions()
+		cfg.FPM.ReservedMemoryMB = reservedMemory
+		cfg.FPM.ProcessMemoryMB = processMemory
+		cfg.FPM.TrafficProfile = profile
+		cfg.FPM.PMType = pm
+		if limit, err := php.GetPHPMemoryLimit(context.Background()); err == nil {
+			cfg.MemoryLimitMB = limit
+		}
+	case "frankenphp", "f":
+		cfg.Runtime = exporter.RuntimeFrankenPHP
+		cfg.FrankenPHP = calculator.DefaultFrankenPHPOptions()
+		cfg.FrankenPHP.ReservedMemoryMB = reservedMemory
+		cfg.FrankenPHP.ThreadMemoryMB = threadMemory
+		cfg.FrankenPHP.TrafficProfile = profile
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown runtime: %s (expected php-fpm or frankenphp)\n", runtime)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exp := exporter.New(cfg)
+	go exp.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exp)
+
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printExporterUsage() {
+	fmt.Println(`Prometheus Exporter
+
+Serves the tuner's view of the host as Prometheus metrics, refreshed on an
+interval: recommended vs configured max_children/num_threads, per-pool PSS
+percentiles, memory budget and headroom, OOM risk score and the reserved
+memory breakdown.
+
+USAGE:
+    php-tuner exporter [options]
+
+OPTIONS:
+    -h, --help              Show help
+    --listen <addr>         Listen address (default: :9253)
+    --runtime <name>        php-fpm or frankenphp (default: php-fpm)
+    --interval <duration>   Refresh interval (default: 15s)
+    --current <file>        Deployed pool file or Caddyfile for configured_* metrics
+    --pool <name>           Pool section to read (default: first pool)
+
+    --traffic, --profiles, --pm, --reserved, --process-mem, --thread-mem, --no-services
+                            Same as the php-fpm and frankenphp commands
+
+EXAMPLES:
+    php-tuner exporter --current /etc/php/8.3/fpm/pool.d/www.conf
+    php-tuner exporter --runtime frankenphp --listen 127.0.0.1:9253`)
+}
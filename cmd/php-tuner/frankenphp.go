@@ -3,12 +3,19 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/muuvmuuv/php-tuner/internal/caddy"
 	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/diff"
+	"github.com/muuvmuuv/php-tuner/internal/framework"
 	"github.com/muuvmuuv/php-tuner/internal/output"
-	"github.com/muuvmuuv/php-tuner/internal/system"
+	"github.com/muuvmuuv/php-tuner/internal/systemd"
 )
 
 func runFrankenPHP(args []string) {
@@ -18,10 +25,26 @@ func runFrankenPHP(args []string) {
 		showHelp       bool
 		noColor        bool
 		onlyConf       bool
-		trafficProfile string
+		traffic        trafficOptions
 		reservedMemory int
 		threadMemory   float64
 		workerMode     bool
+		noServices     bool
+		explain        bool
+		explainFormat  string
+		source         systemSource
+		tmplOpts       templateOptions
+		plateau        int
+		diffOpts       diffOptions
+		frameworkName  string
+		projectDir     string
+		workers        workerSpecs
+		systemdOpts    systemdOptions
+		format         string
+		serverName     string
+		payload        bool
+		caddyAdmin     string
+		exportOpts     exportOptions
 	)
 
 	fs.BoolVar(&showHelp, "help", false, "Show help message")
@@ -29,10 +52,26 @@ func runFrankenPHP(args []string) {
 	fs.BoolVar(&noColor, "no-color", false, "Disable colored output")
 	fs.BoolVar(&onlyConf, "config-only", false, "Output only the configuration")
 	fs.BoolVar(&onlyConf, "c", false, "Output only the configuration (shorthand)")
-	fs.StringVar(&trafficProfile, "traffic", "medium", "Traffic profile: low, medium, high")
+	traffic.register(fs)
 	fs.IntVar(&reservedMemory, "reserved", 0, "Reserved memory in MB for OS/services")
 	fs.Float64Var(&threadMemory, "thread-mem", 0, "Override PHP thread memory in MB")
 	fs.BoolVar(&workerMode, "worker", true, "Enable worker mode")
+	fs.BoolVar(&noServices, "no-services", false, "Do not reserve memory for detected services")
+	fs.IntVar(&plateau, "plateau", 0, "Measured throughput plateau concurrency")
+	fs.BoolVar(&explain, "explain", false, "Show every decision behind the configuration")
+	fs.StringVar(&explainFormat, "explain-format", "text", "Explanation format: text, json")
+	fs.StringVar(&frameworkName, "framework", "", "Framework preset: laravel-octane, symfony")
+	fs.StringVar(&projectDir, "project", "", "Project directory to detect the framework from composer.lock")
+	fs.Var(&workers, "add-worker", "Worker script: file[,share=pct][,mem=MB][,env.NAME=value], repeatable")
+	source.register(fs)
+	tmplOpts.register(fs)
+	diffOpts.register(fs)
+	fs.StringVar(&format, "format", "caddyfile", "Output format: caddyfile, caddy-json, env, dockerfile, compose")
+	fs.StringVar(&serverName, "server-name", "", "SERVER_NAME for the env, dockerfile and compose formats")
+	fs.BoolVar(&payload, "payload", false, "Print the admin API payload of the frankenphp app (caddy-json)")
+	fs.StringVar(&caddyAdmin, "caddy-admin", "", "Caddy admin API to PATCH the frankenphp app to")
+	systemdOpts.register(fs)
+	exportOpts.register(fs)
 
 	fs.Usage = func() { printFrankenPHPUsage() }
 
@@ -45,19 +84,50 @@ func runFrankenPHP(args []string) {
 		return
 	}
 
+	profile := traffic.resolve()
+	tmplOpts.load()
+	switch format {
+	case "caddyfile", "caddy-json", "env", "dockerfile", "compose":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --format %q (use caddyfile, caddy-json, env, dockerfile or compose)\n", format)
+		os.Exit(1)
+	}
+	if format != "caddyfile" && diffOpts.enabled {
+		fmt.Fprintln(os.Stderr, "Error: --diff compares Caddyfiles and cannot be combined with --format")
+		os.Exit(1)
+	}
+	exportOpts.resolve()
+	if exportOpts.wanted() && format != "caddyfile" {
+		fmt.Fprintln(os.Stderr, "Error: --export replaces the config output and cannot be combined with --format")
+		os.Exit(1)
+	}
+	if payload && format != "caddy-json" {
+		fmt.Fprintln(os.Stderr, "Error: --payload requires --format caddy-json")
+		os.Exit(1)
+	}
+	unit, unitNotes := systemdOpts.resolveUnit(systemd.FindFrankenPHPUnit, "frankenphp.service", source.live())
+
+	explainJSON := explainFormatJSON(explainFormat) && explain
+
+	// JSON explanations and templates replace the regular report
+	var out io.Writer = os.Stdout
+	if explainJSON || tmplOpts.wanted() {
+		out = io.Discard
+	}
+
 	// Initialize printer
-	printer := output.NewPrinter(os.Stdout, noColor, onlyConf)
+	printer := output.NewPrinter(out, noColor, onlyConf)
 
 	// Print header
 	printer.PrintFrankenPHPHeader()
 
 	// Detect system info
-	sysInfo, err := system.Detect()
+	env, err := source.load(noProcesses, !noServices && reservedMemory == 0)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error detecting system info: %v\n", err)
 		os.Exit(1)
 	}
-	printer.PrintSystemInfo(sysInfo)
+	printer.PrintSystemInfo(env.System)
 
 	// Build options
 	opts := calculator.DefaultFrankenPHPOptions()
@@ -71,24 +141,199 @@ func runFrankenPHP(args []string) {
 		opts.ThreadMemoryMB = threadMemory
 	}
 
-	switch strings.ToLower(trafficProfile) {
-	case "low":
-		opts.TrafficProfile = calculator.TrafficLow
-	case "high":
-		opts.TrafficProfile = calculator.TrafficHigh
-	default:
-		opts.TrafficProfile = calculator.TrafficMedium
-	}
+	opts.PlateauThreads = plateau
+	opts.TrafficProfile = profile
+	opts.Framework = resolveFramework(frameworkName, projectDir)
+	opts.Workers = workers
 
 	// Calculate configuration
-	cfg := calculator.CalculateFrankenPHP(sysInfo, opts)
+	cfg := calculator.CalculateFrankenPHP(*env, opts)
+	mustValidate(cfg.Validate())
+	if projectDir != "" {
+		// The official image serves the project from /app, where the host
+		// path does not exist
+		container := format == "env" || format == "dockerfile" || format == "compose"
+		for i, worker := range cfg.Workers {
+			switch {
+			case worker.File == "" || filepath.IsAbs(worker.File):
+			case container:
+				cfg.Workers[i].File = path.Join(containerAppDir, filepath.ToSlash(worker.File))
+			default:
+				if abs, err := filepath.Abs(filepath.Join(projectDir, worker.File)); err == nil {
+					cfg.Workers[i].File = abs
+				}
+			}
+		}
+	}
+
+	var dropIn *calculator.SystemdConfig
+	if systemdOpts.wanted() {
+		dropIn = calculator.CalculateSystemdFrankenPHP(*env, cfg)
+		mustValidate(dropIn.Validate())
+		cfg.Trace = append(cfg.Trace, dropIn.Trace...)
+		cfg.Warnings = append(append(cfg.Warnings, unitNotes...), dropIn.Warnings...)
+		cfg.Recommendations = append(cfg.Recommendations, dropIn.Recommendations...)
+
+		// Applied before any output, which --diff, --template and JSON
+		// explanations replace
+		systemdOpts.write(unit, dropIn)
+	}
+	if caddyAdmin != "" {
+		patchCaddy(caddyAdmin, cfg, workerMode)
+	}
+
+	if explainJSON {
+		if err := output.NewPrinter(os.Stdout, noColor, false).PrintExplanationJSON("frankenphp", cfg.Trace); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing explanation: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if tmplOpts.wanted() {
+		tmplOpts.render("frankenphp", *env, cfg, cfg.Directives(), nil, dropIn, cfg.Warnings, cfg.Recommendations, cfg.Trace)
+		return
+	}
 
 	// Print results
 	printer.PrintFrankenPHPCalculation(cfg)
-	printer.PrintFrankenPHPConfig(cfg, workerMode)
+	if explain {
+		printer.PrintExplanation(cfg.Trace)
+	}
+	if !diffOpts.enabled {
+		switch {
+		case exportOpts.wanted():
+			limits := dropIn
+			if limits == nil {
+				limits = calculator.CalculateSystemdFrankenPHP(*env, cfg)
+			}
+			exportOpts.print(printer, cfg.Directives(), limits)
+		case format == "caddyfile":
+			printer.PrintFrankenPHPConfig(cfg, workerMode)
+		case format == "caddy-json":
+			if err := printer.PrintFrankenPHPCaddyJSON(cfg, workerMode, payload); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing Caddy JSON: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			// A container is limited like the systemd unit would be
+			limits := dropIn
+			if limits == nil {
+				limits = calculator.CalculateSystemdFrankenPHP(*env, cfg)
+			}
+			printer.PrintFrankenPHPContainer(cfg, workerMode, output.Container{
+				Format:     format,
+				ServerName: serverName,
+				Limits:     limits,
+			})
+		}
+		if dropIn != nil {
+			printer.PrintSystemdDropIn(dropIn, unit)
+		}
+		printer.PrintFrankenPHPWarnings(cfg)
+		printer.PrintFrankenPHPRecommendations(cfg)
+		if format == "caddyfile" && !exportOpts.wanted() {
+			printer.PrintFrankenPHPUsage()
+		}
+		return
+	}
+
+	path, current := loadCaddyfile(&diffOpts)
+	changes := diff.FrankenPHP(current, cfg)
+	printer.PrintDiff(path, changes, diffOpts.threshold)
 	printer.PrintFrankenPHPWarnings(cfg)
-	printer.PrintFrankenPHPRecommendations(cfg)
-	printer.PrintFrankenPHPUsage()
+	diffOpts.exitOnDrift(changes)
+}
+
+// containerAppDir is the project directory inside the official image
+const containerAppDir = "/app"
+
+// patchCaddy replaces the frankenphp app of a running Caddy. Placeholder
+// worker scripts are never sent.
+func patchCaddy(adminURL string, cfg *calculator.FrankenPHPConfig, workerMode bool) {
+	if workerMode {
+		for _, worker := range cfg.Workers {
+			if worker.File == "" {
+				fmt.Fprintln(os.Stderr, "Error: --caddy-admin needs the worker script, set it with --add-worker or --project")
+				os.Exit(1)
+			}
+		}
+	}
+
+	app, err := caddy.NewApp(cfg, workerMode, "")
+	if err == nil {
+		err = caddy.Patch(adminURL, app)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error updating Caddy: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Patched %s%s\n", strings.TrimSuffix(adminURL, "/"), caddy.AppPath)
+}
+
+// workerSpecs collects repeated --add-worker flags
+type workerSpecs []calculator.WorkerSpec
+
+func (w *workerSpecs) String() string {
+	return fmt.Sprint(len(*w))
+}
+
+// Set parses file[,share=pct][,mem=MB][,env.NAME=value]
+func (w *workerSpecs) Set(value string) error {
+	parts := strings.Split(value, ",")
+	spec := calculator.WorkerSpec{File: strings.TrimSpace(parts[0])}
+	if spec.File == "" {
+		return fmt.Errorf("worker file is required")
+	}
+
+	for _, part := range parts[1:] {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("invalid worker option %q, expected key=value", part)
+		}
+
+		var err error
+		switch {
+		case key == "share":
+			spec.Share, err = strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+		case key == "mem":
+			spec.ThreadMemoryMB, err = strconv.ParseFloat(val, 64)
+		case strings.HasPrefix(key, "env."):
+			spec.Env = append(spec.Env, calculator.Directive{Name: strings.TrimPrefix(key, "env."), Value: val})
+		default:
+			return fmt.Errorf("unknown worker option %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid worker %s %q", key, val)
+		}
+	}
+
+	*w = append(*w, spec)
+	return nil
+}
+
+// resolveFramework returns the --framework preset, or the one detected from
+// the project's composer.lock when no preset is named
+func resolveFramework(name, projectDir string) framework.Framework {
+	if name != "" {
+		fw, err := framework.Parse(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return fw
+	}
+
+	if projectDir == "" {
+		return framework.None
+	}
+
+	fw, err := framework.Detect(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error detecting framework: %v\n", err)
+		os.Exit(1)
+	}
+	return fw
 }
 
 func printFrankenPHPUsage() {
@@ -105,19 +350,74 @@ OPTIONS:
     -c, --config-only   Output only configuration (for piping to file)
     --no-color          Disable colored output
 
-    --traffic <level>   Traffic profile: low, medium, high (default: medium)
-                        - low: Fewer threads, no wait timeout
+    --traffic <name>    Traffic profile (default: medium)
+                        - low: No wait timeout
                         - medium: Balanced threads
-                        - high: More threads, strict timeouts
+                        - high: Strict timeouts
+                        - bursty: Balanced threads for short spikes
+                        - api: Fail fast after 2s
+                        - batch, cron: One thread per core, no wait timeout
+    --profiles <file>   JSON file with custom traffic profiles
 
     --reserved <MB>     Memory to reserve for OS/Caddy in MB
                         Default: auto-calculated (256MB + 10% of total)
+                        plus memory of detected co-located services
+
+    --no-services       Do not reserve memory for detected services
+                        (MySQL, PostgreSQL, Redis, Elasticsearch, ...)
+
+    --plateau <n>       Cap threads near a measured throughput plateau
+                        (see 'php-tuner bench')
 
     --thread-mem <MB>   Override estimated thread memory in MB
                         Default: 30MB (FrankenPHP threads share memory)
 
     --worker=false      Disable worker mode (not recommended)
 
+    --add-worker <def>  Worker script, repeat for several workers:
+                        file[,share=pct][,mem=MB][,env.NAME=value]
+                        Threads are split by share (default: evenly)
+
+    --framework <name>  Worker-mode preset: laravel-octane, symfony
+                        Sets thread memory, worker restarts and env
+    --project <dir>     Detect the framework from composer.lock in dir
+
+    --explain           Show every decision behind the configuration
+    --explain-format <f>  Explanation format: text, json (default: text)
+    --template <file>   Render the result through a Go text/template
+
+    --format <f>        Output format (default: caddyfile)
+                        - caddyfile: frankenphp global options
+                        - caddy-json: Caddy JSON config with the frankenphp app
+                        - env: FRANKENPHP_CONFIG and SERVER_NAME for an env_file
+                        - dockerfile: Dockerfile for the official image
+                        - compose: compose service with deploy.resources.limits
+    --server-name <s>   SERVER_NAME for env, dockerfile and compose (e.g. :80)
+    --payload           With caddy-json, print only the frankenphp app as sent
+                        to the admin API at /config/apps/frankenphp
+    --caddy-admin <url> PATCH the frankenphp app of a running Caddy through
+                        its admin API, e.g. http://localhost:2019
+
+    --export <target>   Print variables instead of the Caddyfile:
+                        ansible, terraform or helm (with resources.limits)
+    --export-map <file> JSON file mapping values to your own keys
+
+    --systemd           Print a unit drop-in with MemoryHigh, MemoryMax,
+                        CPUQuota, LimitNOFILE, TasksMax and OOMScoreAdjust
+    --unit <name>       systemd unit (default: frankenphp.service)
+    --apply             Write the drop-in and run systemctl daemon-reload
+
+    --diff              Compare with the deployed Caddyfile
+    --current <file>    Caddyfile to compare (default: auto-detect)
+    --drift-threshold <pct>  Exit with code 3 above this drift (default: 20)
+
+    --simulate-cpu <n>  Simulate a machine with n CPU cores (default: 2)
+    --simulate-mem <s>  Simulate a machine with this memory, e.g. 8G (default: 4G)
+    --system-from <f>   Read system information from a JSON file
+                        (skips all host detection)
+    --from-snapshot <f> Read all host information from a snapshot
+                        (see 'php-tuner snapshot')
+
 EXAMPLES:
     # Auto-detect everything
     php-tuner frankenphp
@@ -128,9 +428,31 @@ EXAMPLES:
     # Export config to file
     php-tuner f --config-only > Caddyfile.snippet
 
+    # Laravel Octane or Symfony Runtime app
+    php-tuner f --project /var/www/app
+
+    # App and admin workers with their own thread budgets
+    php-tuner f --add-worker public/index.php,share=80 \\
+                --add-worker admin/index.php,share=20,mem=80
+
+    # Docker Compose service with CPU and memory limits
+    php-tuner f --format compose --server-name :80
+
+    # Update a Caddy managed through its JSON admin API
+    php-tuner f --add-worker /app/public/index.php --caddy-admin http://localhost:2019
+
+    # Enforce the memory budget with a systemd drop-in
+    php-tuner f --systemd --apply
+
     # Custom thread memory estimate
     php-tuner f --thread-mem 50
 
+    # Size a machine before buying it
+    php-tuner f --simulate-cpu 8 --simulate-mem 16G
+
+    # Trace how every value was derived
+    php-tuner f --explain
+
 OUTPUT:
     The configuration is output in Caddyfile format, ready to be added
     to your FrankenPHP Caddyfile configuration.`)
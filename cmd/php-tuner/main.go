@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/services"
 )
 
 var version = "dev"
@@ -20,6 +23,26 @@ func main() {
 		runFrankenPHP(os.Args[2:])
 	case "php-fpm", "fpm":
 		runPHPFPM(os.Args[2:])
+	case "compare":
+		runCompare(os.Args[2:])
+	case "schedule":
+		runSchedule(os.Args[2:])
+	case "snapshot":
+		runSnapshot(os.Args[2:])
+	case "watch":
+		runWatch(os.Args[2:])
+	case "exporter":
+		runExporter(os.Args[2:])
+	case "apache":
+		runApache(os.Args[2:])
+	case "roadrunner", "rr":
+		runRoadRunner(os.Args[2:])
+	case "swoole", "openswoole":
+		runSwoole(os.Args[2:])
+	case "bench":
+		runBench(os.Args[2:])
+	case "autotune":
+		runAutotune(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Printf("php-tuner %s\n", version)
 	case "help", "-h", "--help":
@@ -45,6 +68,16 @@ USAGE:
 COMMANDS:
     frankenphp, f    FrankenPHP configuration (default)
     php-fpm, fpm     PHP-FPM configuration
+    apache           Apache prefork configuration (mod_php)
+    roadrunner, rr   RoadRunner worker pool configuration
+    swoole           Swoole/OpenSwoole server configuration
+    compare          Compare configurations across machine shapes
+    schedule         Configurations for time-of-day traffic windows
+    snapshot         Capture host information as JSON
+    watch            Alert on saturation and config drift
+    exporter         Serve tuner metrics for Prometheus
+    bench            Load test to find the throughput plateau
+    autotune         Adjust workers stepwise against SLOs
     help             Show this help
     version          Show version
 
@@ -52,9 +85,18 @@ EXAMPLES:
     php-tuner                           # FrankenPHP (default)
     php-tuner f --traffic high          # High-traffic FrankenPHP
     php-tuner fpm                       # PHP-FPM
-    php-tuner fpm --apply --restart     # PHP-FPM with auto-apply
+    php-tuner fpm --systemd --apply     # PHP-FPM with kernel-enforced limits
 
 Run 'php-tuner <command> --help' for command options.
 
 https://github.com/muuvmuuv/php-tuner`)
 }
+
+func detectServices() []services.Service {
+	svcs, err := services.Detect(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not detect co-located services: %v\n", err)
+		return nil
+	}
+	return svcs
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// trafficOptions selects a built-in or custom traffic profile
+type trafficOptions struct {
+	name     string
+	profiles string
+	custom   map[string]calculator.Profile
+}
+
+func (t *trafficOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&t.name, "traffic", "medium", "Traffic profile")
+	fs.StringVar(&t.profiles, "profiles", "", "JSON file with custom traffic profiles")
+}
+
+// resolve returns the selected profile; unknown names exit instead of
+// falling back to medium
+func (t *trafficOptions) resolve() calculator.Profile {
+	return t.lookup(t.name)
+}
+
+// lookup returns a built-in or custom profile by name and exits on unknown
+// names
+func (t *trafficOptions) lookup(name string) calculator.Profile {
+	if t.profiles != "" && t.custom == nil {
+		var err error
+		if t.custom, err = calculator.LoadProfiles(t.profiles); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading profiles: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	profile, err := calculator.LookupProfile(name, t.custom)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return profile
+}
+
+// parsePMType converts --pm; empty or auto lets the profile decide and
+// unknown types exit instead of falling back to it
+func parsePMType(name string) calculator.PMType {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return ""
+	case "static":
+		return calculator.PMStatic
+	case "dynamic":
+		return calculator.PMDynamic
+	case "ondemand":
+		return calculator.PMOnDemand
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown process manager %q (use static, dynamic or ondemand)\n", name)
+		os.Exit(1)
+		return ""
+	}
+}
+
+// explainFormatJSON checks --explain-format and reports whether it asks for
+// JSON; unknown formats exit even without --explain
+func explainFormatJSON(format string) bool {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "text":
+		return false
+	case "json":
+		return true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --explain-format %q (use text or json)\n", format)
+		os.Exit(1)
+		return false
+	}
+}
+
+// mustValidate stops before printing a config the runtime would refuse
+func mustValidate(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
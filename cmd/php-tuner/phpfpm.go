@@ -3,13 +3,15 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"strings"
 
 	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/diff"
+	"github.com/muuvmuuv/php-tuner/internal/nginx"
 	"github.com/muuvmuuv/php-tuner/internal/output"
-	"github.com/muuvmuuv/php-tuner/internal/php"
-	"github.com/muuvmuuv/php-tuner/internal/system"
+	"github.com/muuvmuuv/php-tuner/internal/systemd"
 )
 
 func runPHPFPM(args []string) {
@@ -20,9 +22,21 @@ func runPHPFPM(args []string) {
 		noColor        bool
 		onlyConf       bool
 		pmType         string
-		trafficProfile string
+		traffic        trafficOptions
 		reservedMemory int
 		processMemory  float64
+		noServices     bool
+		explain        bool
+		explainFormat  string
+		source         systemSource
+		tmplOpts       templateOptions
+		plateau        int
+		diffOpts       diffOptions
+		pool           string
+		withNginx      bool
+		nginxConf      string
+		systemdOpts    systemdOptions
+		exportOpts     exportOptions
 	)
 
 	fs.BoolVar(&showHelp, "help", false, "")
@@ -31,9 +45,21 @@ func runPHPFPM(args []string) {
 	fs.BoolVar(&onlyConf, "config-only", false, "")
 	fs.BoolVar(&onlyConf, "c", false, "")
 	fs.StringVar(&pmType, "pm", "", "")
-	fs.StringVar(&trafficProfile, "traffic", "medium", "")
+	traffic.register(fs)
 	fs.IntVar(&reservedMemory, "reserved", 0, "")
 	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+	fs.IntVar(&plateau, "plateau", 0, "")
+	fs.BoolVar(&explain, "explain", false, "")
+	fs.StringVar(&explainFormat, "explain-format", "text", "")
+	source.register(fs)
+	tmplOpts.register(fs)
+	diffOpts.register(fs)
+	systemdOpts.register(fs)
+	exportOpts.register(fs)
+	fs.StringVar(&pool, "pool", "", "")
+	fs.BoolVar(&withNginx, "nginx", false, "")
+	fs.StringVar(&nginxConf, "nginx-conf", "", "")
 
 	fs.Usage = func() { printPHPFPMUsage() }
 
@@ -46,22 +72,30 @@ func runPHPFPM(args []string) {
 		return
 	}
 
-	printer := output.NewPrinter(os.Stdout, noColor, onlyConf)
+	profile := traffic.resolve()
+	pm := parsePMType(pmType)
+	tmplOpts.load()
+	exportOpts.resolve()
+	unit, unitNotes := systemdOpts.resolveUnit(systemd.FindFPMUnit, "php-fpm.service", source.live())
+
+	explainJSON := explainFormatJSON(explainFormat) && explain
+
+	// JSON explanations and templates replace the regular report
+	var out io.Writer = os.Stdout
+	if explainJSON || tmplOpts.wanted() {
+		out = io.Discard
+	}
+
+	printer := output.NewPrinter(out, noColor, onlyConf)
 	printer.PrintHeader()
 
-	sysInfo, err := system.Detect()
+	env, err := source.load(fpmProcesses, !noServices && reservedMemory == 0)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error detecting system info: %v\n", err)
 		os.Exit(1)
 	}
-	printer.PrintSystemInfo(sysInfo)
-
-	phpInfo, err := php.DetectProcesses()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Could not detect PHP processes: %v\n", err)
-		phpInfo = &php.ProcessInfo{}
-	}
-	printer.PrintPHPInfo(phpInfo)
+	printer.PrintSystemInfo(env.System)
+	printer.PrintPHPInfo(env.PHP)
 
 	opts := calculator.DefaultOptions()
 
@@ -73,31 +107,115 @@ func runPHPFPM(args []string) {
 		opts.ProcessMemoryMB = processMemory
 	}
 
-	switch strings.ToLower(trafficProfile) {
-	case "low":
-		opts.TrafficProfile = calculator.TrafficLow
-	case "high":
-		opts.TrafficProfile = calculator.TrafficHigh
-	default:
-		opts.TrafficProfile = calculator.TrafficMedium
+	opts.PlateauWorkers = plateau
+	opts.TrafficProfile = profile
+	opts.PMType = pm
+
+	cfg := calculator.Calculate(*env, opts)
+
+	var ngx *calculator.NginxConfig
+	if withNginx || nginxConf != "" {
+		ngx, nginxConf = tuneNginx(env, cfg, nginxConf, diffOpts.current, pool, source.live())
 	}
+	mustValidate(cfg.Validate())
 
-	switch strings.ToLower(pmType) {
-	case "static":
-		opts.PMType = calculator.PMStatic
-	case "dynamic":
-		opts.PMType = calculator.PMDynamic
-	case "ondemand":
-		opts.PMType = calculator.PMOnDemand
+	var dropIn *calculator.SystemdConfig
+	if systemdOpts.wanted() {
+		dropIn = calculator.CalculateSystemdFPM(*env, cfg)
+		mustValidate(dropIn.Validate())
+		cfg.Trace = append(cfg.Trace, dropIn.Trace...)
+		cfg.Warnings = append(append(cfg.Warnings, unitNotes...), dropIn.Warnings...)
+		cfg.Recommendations = append(cfg.Recommendations, dropIn.Recommendations...)
+
+		// Applied before any output, which --diff, --template and JSON
+		// explanations replace
+		systemdOpts.write(unit, dropIn)
+	}
+
+	if explainJSON {
+		if err := output.NewPrinter(os.Stdout, noColor, false).PrintExplanationJSON("php-fpm", cfg.Trace); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing explanation: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	cfg := calculator.Calculate(sysInfo, phpInfo, opts)
+	if tmplOpts.wanted() {
+		tmplOpts.render("php-fpm", *env, cfg, cfg.Directives(), ngx, dropIn, cfg.Warnings, cfg.Recommendations, cfg.Trace)
+		return
+	}
 
 	printer.PrintCalculation(cfg)
-	printer.PrintConfig(cfg)
+	if explain {
+		printer.PrintExplanation(cfg.Trace)
+	}
+	if !diffOpts.enabled {
+		if exportOpts.wanted() {
+			limits := dropIn
+			if limits == nil {
+				limits = calculator.CalculateSystemdFPM(*env, cfg)
+			}
+			exportOpts.print(printer, cfg.Directives(), limits)
+		} else {
+			printer.PrintConfig(cfg)
+		}
+		if ngx != nil {
+			printer.PrintNginxConfig(ngx, nginxConf)
+		}
+		if dropIn != nil {
+			printer.PrintSystemdDropIn(dropIn, unit)
+		}
+		printer.PrintWarnings(cfg)
+		printer.PrintRecommendations(cfg)
+		if !exportOpts.wanted() {
+			printer.PrintUsage()
+		}
+		return
+	}
+
+	path, current := loadFPMPool(&diffOpts, pool)
+	changes := diff.FPM(current, cfg)
+	printer.PrintDiff(path, changes, diffOpts.threshold)
 	printer.PrintWarnings(cfg)
-	printer.PrintRecommendations(cfg)
-	printer.PrintUsage()
+	diffOpts.exitOnDrift(changes)
+}
+
+// tuneNginx aligns nginx with the pool and merges its pool settings,
+// decisions, warnings and recommendations into cfg. Deployed nginx and pool
+// files are only looked up on a live host; it returns the nginx.conf path.
+func tuneNginx(env *calculator.Input, cfg *calculator.Config, confPath, poolPath, pool string, live bool) (*calculator.NginxConfig, string) {
+	if confPath == "" && live {
+		confPath, _ = nginx.Find()
+	}
+
+	var current *nginx.Config
+	if confPath != "" {
+		parsed, err := nginx.Parse(confPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading nginx config: %v\n", err)
+			os.Exit(1)
+		}
+		current = parsed
+	}
+
+	var opts calculator.NginxOptions
+	if poolPath == "" && live {
+		poolPath, _ = deployed.FindFPMPool()
+	}
+	if poolPath != "" {
+		if directives, err := deployed.ParseFPMPool(poolPath, pool); err == nil {
+			opts.PoolListen = directives["listen"]
+			opts.RequestTerminateTimeout = directives["request_terminate_timeout"]
+		}
+	}
+
+	ngx := calculator.CalculateNginx(env.System, cfg, current, opts)
+	cfg.RequestTerminateTimeout, cfg.ListenBacklog = ngx.RequestTerminateTimeout, ngx.ListenBacklog
+	cfg.Trace = append(cfg.Trace, ngx.Trace...)
+	cfg.Warnings = append(cfg.Warnings, ngx.Warnings...)
+	cfg.Recommendations = append(cfg.Recommendations, ngx.Recommendations...)
+
+	return ngx, confPath
 }
 
 func printPHPFPMUsage() {
@@ -112,12 +230,53 @@ OPTIONS:
     -c, --config-only   Output only configuration
     --no-color          Disable colors
     --pm <type>         static, dynamic, ondemand (default: auto)
-    --traffic <level>   low, medium, high (default: medium)
+    --traffic <name>    low, medium, high, bursty, api, batch, cron (default: medium)
+    --profiles <file>   JSON file with custom traffic profiles
     --reserved <MB>     Reserved memory for OS/services
     --process-mem <MB>  Override PHP process memory
+    --no-services       Do not reserve memory for detected services
+                        (MySQL, PostgreSQL, Redis, Elasticsearch, ...)
+    --plateau <n>       Cap max_children near a measured throughput plateau
+                        (see 'php-tuner bench')
+    --explain           Show every decision behind the configuration
+    --explain-format <f>  text or json (default: text)
+    --template <file>   Render the result through a Go text/template
+
+    --nginx             Tune nginx in front of the pool as well; sets
+                        request_terminate_timeout and listen.backlog
+    --nginx-conf <file> nginx.conf to align with (default: auto-detect,
+                        implies --nginx)
+
+    --systemd           Print a unit drop-in with MemoryHigh, MemoryMax,
+                        CPUQuota, LimitNOFILE, TasksMax and OOMScoreAdjust
+    --unit <name>       systemd unit (default: auto-detect, e.g. php8.3-fpm.service)
+    --apply             Write the drop-in and run systemctl daemon-reload
+
+    --export <target>   Print variables instead of the pool config:
+                        - ansible: geerlingguy.php group_vars
+                        - terraform: .tfvars with CPU and memory limits
+                        - helm: values with resources.limits
+    --export-map <file> JSON file mapping values to your own keys
+
+    --diff              Compare with the deployed pool configuration
+    --current <file>    Pool file to compare (default: auto-detect www.conf)
+    --pool <name>       Pool section to compare (default: first pool)
+    --drift-threshold <pct>  Exit with code 3 above this drift (default: 20)
+
+    --simulate-cpu <n>  Simulate a machine with n CPU cores (default: 2)
+    --simulate-mem <s>  Simulate a machine with this memory, e.g. 8G (default: 4G)
+    --system-from <f>   Read system information from a JSON file
+    --from-snapshot <f> Read all host information from a snapshot
+                        (see 'php-tuner snapshot')
 
 EXAMPLES:
     php-tuner fpm
     php-tuner fpm --traffic high --pm static
-    php-tuner fpm -c > www.conf`)
+    php-tuner fpm -c > www.conf
+    php-tuner fpm --explain --explain-format json
+    php-tuner fpm --simulate-cpu 8 --simulate-mem 32G
+    php-tuner fpm --nginx
+    php-tuner fpm --systemd --apply
+    php-tuner fpm --export ansible -c > group_vars/web/php_fpm.yml
+    php-tuner fpm --diff --current /etc/php/8.3/fpm/pool.d/www.conf`)
 }
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+	"github.com/muuvmuuv/php-tuner/internal/systemd"
+)
+
+func runSchedule(args []string) {
+	fs := flag.NewFlagSet("schedule", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		noColor        bool
+		onlyConf       bool
+		runtime        string
+		emit           string
+		latency        time.Duration
+		traffic        trafficOptions
+		reservedMemory int
+		processMemory  float64
+		threadMemory   float64
+		noServices     bool
+		source         systemSource
+		current        string
+		pool           string
+		dir            string
+		reload         string
+		write          bool
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.BoolVar(&onlyConf, "config-only", false, "")
+	fs.BoolVar(&onlyConf, "c", false, "")
+	fs.StringVar(&runtime, "runtime", "frankenphp", "")
+	fs.StringVar(&emit, "emit", "bounds", "")
+	fs.DurationVar(&latency, "latency", 100*time.Millisecond, "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.Float64Var(&threadMemory, "thread-mem", 0, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+	source.register(fs)
+	fs.StringVar(&current, "current", "", "")
+	fs.StringVar(&pool, "pool", "", "")
+	fs.StringVar(&dir, "dir", "/etc/php-tuner/schedule", "")
+	fs.StringVar(&reload, "reload", "", "")
+	fs.BoolVar(&write, "write", false, "")
+
+	fs.Usage = func() { printScheduleUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printScheduleUsage()
+		return
+	}
+
+	// Rate-only windows use the --traffic profile
+	profile := traffic.resolve()
+
+	var windows []calculator.Window
+	for _, spec := range fs.Args() {
+		w, err := parseWindow(spec, profile, &traffic)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		windows = append(windows, w)
+	}
+	if len(windows) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one window is required, e.g. 08-18=high 18-08=low")
+		os.Exit(1)
+	}
+	if err := calculator.CheckWindows(windows); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if latency <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --latency must be positive")
+		os.Exit(1)
+	}
+
+	fpm := false
+	switch strings.ToLower(runtime) {
+	case "php-fpm", "fpm":
+		fpm = true
+	case "frankenphp", "f":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown runtime: %s (expected frankenphp or php-fpm)\n", runtime)
+		os.Exit(1)
+	}
+
+	switch emit {
+	case "bounds":
+	case "cron", "systemd":
+		// FrankenPHP scales threads itself, swapping Caddyfiles would also
+		// have to rewrite every worker block
+		if !fpm {
+			fmt.Fprintf(os.Stderr, "Error: --emit %s swaps PHP-FPM pools; FrankenPHP covers the day with --emit bounds\n", emit)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown --emit %q (use bounds, cron or systemd)\n", emit)
+		os.Exit(1)
+	}
+
+	printer := output.NewPrinter(os.Stdout, noColor, onlyConf)
+	printer.PrintScheduleHeader()
+
+	procs := noProcesses
+	if fpm {
+		procs = fpmProcesses
+	}
+	env, err := source.load(procs, !noServices && reservedMemory == 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error detecting system info: %v\n", err)
+		os.Exit(1)
+	}
+	printer.PrintSystemInfo(env.System)
+
+	if !fpm {
+		opts := calculator.DefaultFrankenPHPOptions()
+		opts.ReservedMemoryMB = reservedMemory
+		opts.ThreadMemoryMB = threadMemory
+
+		s := calculator.CalculateFrankenPHPSchedule(*env, opts, windows, latency)
+		for _, cfg := range s.Configs {
+			mustValidate(cfg.Validate())
+		}
+		mustValidate(s.Day.Validate())
+
+		printer.PrintFrankenPHPSchedule(s)
+		printer.PrintFrankenPHPConfig(s.Day, opts.WorkerMode)
+		printer.PrintFrankenPHPWarnings(s.Day)
+		printer.PrintFrankenPHPRecommendations(s.Day)
+		return
+	}
+
+	opts := calculator.DefaultOptions()
+	opts.ReservedMemoryMB = reservedMemory
+	opts.ProcessMemoryMB = processMemory
+
+	s := calculator.CalculateSchedule(*env, opts, windows, latency)
+	for _, cfg := range s.Configs {
+		mustValidate(cfg.Validate())
+	}
+	mustValidate(s.Day.Validate())
+
+	printer.PrintSchedule(s)
+
+	if emit == "bounds" {
+		printer.PrintConfig(s.Day)
+		printer.PrintWarnings(s.Day)
+		printer.PrintRecommendations(s.Day)
+		return
+	}
+
+	target := locateConfig(current, deployed.FindFPMPool)
+	if reload == "" {
+		reload = "systemctl reload " + fpmReloadUnit(source.live())
+	}
+	swaps := make([]output.Swap, 0, len(windows))
+	for i, w := range windows {
+		swap := output.Swap{
+			Window: w,
+			Source: filepath.Join(dir, windowFile(target, pool, w)),
+			Target: target,
+			Reload: reload,
+		}
+		if write {
+			if err := writeWindowPool(swap, pool, s.Configs[i]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", swap.Source, err)
+				os.Exit(1)
+			}
+		}
+		swaps = append(swaps, swap)
+	}
+
+	if emit == "cron" {
+		printer.PrintCron(swaps)
+	} else {
+		printer.PrintSystemdTimers(swaps)
+	}
+	printer.PrintWarnings(s.Day)
+	if !write && !onlyConf {
+		fmt.Fprintf(os.Stderr, "Run with --write to create the window pool configs in %s\n", dir)
+	}
+}
+
+// parseWindow parses HH-HH=<profile>, HH-HH=<n>rps or HH-HH=<profile>:<n>rps
+func parseWindow(spec string, fallback calculator.Profile, traffic *trafficOptions) (calculator.Window, error) {
+	hours, value, ok := strings.Cut(spec, "=")
+	from, to, ok2 := strings.Cut(hours, "-")
+	if !ok || !ok2 || value == "" {
+		return calculator.Window{}, fmt.Errorf("invalid window %q, expected HH-HH=<profile> or HH-HH=<n>rps", spec)
+	}
+
+	w := calculator.Window{Profile: fallback}
+	var err error
+	if w.Start, err = strconv.Atoi(strings.TrimSpace(from)); err != nil {
+		return w, fmt.Errorf("invalid window start %q", from)
+	}
+	if w.End, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+		return w, fmt.Errorf("invalid window end %q", to)
+	}
+
+	name, rate, _ := strings.Cut(value, ":")
+	if strings.HasSuffix(name, "rps") {
+		name, rate = "", name
+	}
+	if name != "" {
+		w.Profile = traffic.lookup(name)
+	}
+	if rate != "" {
+		w.RPS, err = strconv.ParseFloat(strings.TrimSuffix(rate, "rps"), 64)
+		if err != nil || w.RPS <= 0 {
+			return w, fmt.Errorf("invalid rate %q in window %q, expected e.g. 400rps", rate, spec)
+		}
+	}
+	return w, nil
+}
+
+// windowFile names the pool config of a window after the pool, e.g.
+// www-08-18.conf
+func windowFile(target, pool string, w calculator.Window) string {
+	if pool == "" {
+		pool = strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+	}
+	return fmt.Sprintf("%s-%s.conf", pool, w)
+}
+
+// writeWindowPool copies the deployed pool and sets the window's directives,
+// keeping listen, user and every other setting of the original
+func writeWindowPool(swap output.Swap, pool string, cfg *calculator.Config) error {
+	data, err := os.ReadFile(swap.Target)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(swap.Source), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(swap.Source, data, 0o644); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, d := range cfg.Directives() {
+		errs = append(errs, deployed.SetFPMDirective(swap.Source, pool, d.Name, d.Value))
+	}
+	return errors.Join(errs...)
+}
+
+func printScheduleUsage() {
+	fmt.Println(`Traffic Schedule
+
+Calculates a configuration for each time window of the day. Either prints
+one configuration whose bounds cover every window, or emits cron entries or
+systemd timers that swap PHP-FPM pool configs when a window starts.
+
+USAGE:
+    php-tuner schedule [options] <window>...
+
+WINDOWS:
+    HH-HH=<profile>         Traffic profile during the window
+    HH-HH=<n>rps            Expected requests per second (uses --traffic)
+    HH-HH=<profile>:<n>rps  Both
+    Windows end before the second hour and may wrap past midnight; together
+    they must cover all 24 hours exactly once.
+
+OPTIONS:
+    -h, --help          Show help
+    -c, --config-only   Output only the configuration or schedule
+    --no-color          Disable colors
+    --runtime <name>    frankenphp or php-fpm (default: frankenphp)
+    --emit <target>     bounds, cron or systemd (default: bounds)
+                        - bounds: one config, dynamic PM or max_threads
+                          sized for the busiest window
+                        - cron, systemd: swap PHP-FPM pools per window
+    --latency <dur>     Mean response time for rate windows (default: 100ms)
+    --traffic <name>    Profile for windows given as a rate (default: medium)
+    --profiles <file>   JSON file with custom traffic profiles
+    --reserved <MB>     Reserved memory for OS/services
+    --process-mem <MB>  Override PHP-FPM process memory
+    --thread-mem <MB>   Override FrankenPHP thread memory
+    --no-services       Don't reserve memory for detected services
+    --simulate-cpu <n>  Simulate CPU cores
+    --simulate-mem <sz> Simulate memory, e.g. 8G
+    --system-from <f>   Read system info from JSON
+    --from-snapshot <f> Read host info from a snapshot
+
+SWAPPING (cron, systemd):
+    --current <file>    Deployed pool config (default: auto-detect)
+    --pool <name>       Pool section to rewrite (default: first)
+    --dir <dir>         Where the window pool configs live
+                        (default: /etc/php-tuner/schedule)
+    --write             Create the window pool configs in --dir from --current
+    --reload <command>  Reload command (default: systemctl reload <unit>, the
+                        installed PHP-FPM unit or php-fpm)
+
+EXAMPLES:
+    # Business hours are 10x busier than the night
+    php-tuner schedule --runtime fpm 08-18=high 18-08=low
+
+    # Size from expected traffic, 150ms mean response time
+    php-tuner schedule --runtime fpm --latency 150ms 08-18=400rps 18-08=40rps
+
+    # Swap pools with systemd timers
+    php-tuner schedule --runtime fpm --emit systemd --write 08-18=high 18-08=low`)
+}
+
+// fpmReloadUnit returns the installed PHP-FPM unit, e.g. php8.3-fpm on
+// Debian, falling back to php-fpm when the host is not inspected
+func fpmReloadUnit(live bool) string {
+	if live {
+		if unit, err := systemd.FindFPMUnit(); err == nil {
+			return strings.TrimSuffix(unit, ".service")
+		}
+	}
+	return "php-fpm"
+}
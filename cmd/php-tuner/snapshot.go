@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/snapshot"
+)
+
+func runSnapshot(args []string) {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+
+	var (
+		showHelp  bool
+		statusURL string
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.StringVar(&statusURL, "status-url", "", "")
+
+	fs.Usage = func() { printSnapshotUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printSnapshotUsage()
+		return
+	}
+
+	snap, err := snapshot.Capture(context.Background(), statusURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error capturing snapshot: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := snap.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing snapshot: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printSnapshotUsage() {
+	fmt.Println(`Host Snapshot
+
+Captures system information, PHP processes, php.ini settings, cgroup
+limits, co-located services and PHP-FPM status metrics as JSON, so the
+configuration can be calculated on another machine.
+
+USAGE:
+    php-tuner snapshot [options] > host.json
+
+OPTIONS:
+    -h, --help          Show help
+    --status-url <url>  PHP-FPM status page URL (pm.status_path)
+
+EXAMPLES:
+    php-tuner snapshot > host.json
+    php-tuner snapshot --status-url http://127.0.0.1/fpm-status > host.json
+
+    # Elsewhere
+    php-tuner fpm --from-snapshot host.json
+    php-tuner f --from-snapshot host.json`)
+}
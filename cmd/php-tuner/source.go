@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/snapshot"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// systemSource selects where the calculator inputs come from: the real host,
+// a snapshot, a JSON file or a simulated machine shape
+type systemSource struct {
+	simulateCPU  int
+	simulateMem  string
+	systemFrom   string
+	fromSnapshot string
+}
+
+func (s *systemSource) register(fs *flag.FlagSet) {
+	fs.IntVar(&s.simulateCPU, "simulate-cpu", 0, "Simulate a machine with this many CPU cores")
+	fs.StringVar(&s.simulateMem, "simulate-mem", "", "Simulate a machine with this much memory (e.g. 8G)")
+	fs.StringVar(&s.systemFrom, "system-from", "", "Read system information from a JSON file")
+	fs.StringVar(&s.fromSnapshot, "from-snapshot", "", "Read all host information from a snapshot file")
+}
+
+// simulated reports whether the machine is hypothetical
+func (s *systemSource) simulated() bool {
+	return s.simulateCPU > 0 || s.simulateMem != "" || s.systemFrom != ""
+}
+
+// live reports whether the inputs are detected on this host
+func (s *systemSource) live() bool {
+	return s.fromSnapshot == "" && !s.simulated()
+}
+
+// processes selects the PHP processes a runtime is sized by, detected on
+// the host or read from a snapshot
+type processes struct {
+	name     string
+	detect   func(context.Context) (*php.ProcessInfo, error)
+	captured func(*snapshot.Snapshot) *php.ProcessInfo
+}
+
+var (
+	noProcesses  = processes{}
+	fpmProcesses = processes{"PHP processes", php.DetectProcesses,
+		func(s *snapshot.Snapshot) *php.ProcessInfo { return s.Processes }}
+	apacheProcesses = processes{"Apache processes", php.DetectApacheProcesses,
+		func(s *snapshot.Snapshot) *php.ProcessInfo { return s.Apache }}
+	roadRunnerWorkers = processes{"RoadRunner workers", php.DetectRoadRunnerWorkers,
+		func(s *snapshot.Snapshot) *php.ProcessInfo { return s.RoadRunner }}
+	swooleWorkers = processes{"Swoole workers", php.DetectSwooleWorkers,
+		func(s *snapshot.Snapshot) *php.ProcessInfo { return s.Swoole }}
+)
+
+// load gathers the calculator input; PHP processes and services are only
+// detected when requested since not every runtime needs them
+func (s *systemSource) load(procs processes, withServices bool) (*calculator.Input, error) {
+	if s.fromSnapshot != "" && s.systemFrom != "" {
+		return nil, errors.New("--from-snapshot and --system-from are mutually exclusive")
+	}
+
+	env := &calculator.Input{PHP: &php.ProcessInfo{}}
+
+	switch {
+	case s.fromSnapshot != "":
+		snap, err := snapshot.Load(s.fromSnapshot)
+		if err != nil {
+			return nil, err
+		}
+		env.System = snap.System
+		env.MemoryLimitMB = snap.MemoryLimitMB()
+		if procs.captured != nil {
+			env.PHP = procs.captured(snap)
+		}
+		if withServices {
+			env.Services = snap.Services
+		}
+
+	case s.simulated():
+		// Simulated machines have no PHP processes or services to inspect
+		env.System = system.Simulate(2, 4096)
+		if s.systemFrom != "" {
+			loaded, err := system.Load(s.systemFrom)
+			if err != nil {
+				return nil, err
+			}
+			env.System = loaded
+		}
+
+	default:
+		sysInfo, err := system.Detect()
+		if err != nil {
+			return nil, err
+		}
+		env.System = sysInfo
+
+		ctx := context.Background()
+		if procs.detect != nil {
+			if env.PHP, err = procs.detect(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Could not detect %s: %v\n", procs.name, err)
+				env.PHP = &php.ProcessInfo{}
+			}
+			if limit, err := php.GetPHPMemoryLimit(ctx); err == nil {
+				env.MemoryLimitMB = limit
+			}
+		}
+		if withServices {
+			env.Services = detectServices()
+		}
+	}
+
+	return env, s.applyOverrides(env.System)
+}
+
+// applyOverrides replaces CPU and memory with the simulated shape
+func (s *systemSource) applyOverrides(info *system.Info) error {
+	if s.simulateCPU > 0 {
+		info.CPUCores = s.simulateCPU
+	}
+
+	if s.simulateMem != "" {
+		memMB, err := system.ParseSizeMB(s.simulateMem)
+		if err != nil {
+			return fmt.Errorf("--simulate-mem: %w", err)
+		}
+		info.MemUsedMB = info.MemTotalMB - info.MemAvailMB
+		info.MemTotalMB = memMB
+		info.MemAvailMB = max(memMB-info.MemUsedMB, 0)
+		info.MemFreeMB = info.MemAvailMB
+	}
+
+	return nil
+}
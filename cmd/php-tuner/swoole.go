@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+)
+
+func runSwoole(args []string) {
+	fs := flag.NewFlagSet("swoole", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		noColor        bool
+		onlyConf       bool
+		traffic        trafficOptions
+		reservedMemory int
+		workerMemory   float64
+		coroutine      bool
+		taskWorkers    bool
+		noServices     bool
+		explain        bool
+		explainFormat  string
+		source         systemSource
+		tmplOpts       templateOptions
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.BoolVar(&noColor, "no-color", false, "")
+	fs.BoolVar(&onlyConf, "config-only", false, "")
+	fs.BoolVar(&onlyConf, "c", false, "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&workerMemory, "worker-mem", 0, "")
+	fs.BoolVar(&coroutine, "coroutine", true, "")
+	fs.BoolVar(&taskWorkers, "task-workers", false, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+	fs.BoolVar(&explain, "explain", false, "")
+	fs.StringVar(&explainFormat, "explain-format", "text", "")
+	source.register(fs)
+	tmplOpts.register(fs)
+
+	fs.Usage = func() { printSwooleUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printSwooleUsage()
+		return
+	}
+
+	profile := traffic.resolve()
+	tmplOpts.load()
+
+	explainJSON := explainFormatJSON(explainFormat) && explain
+
+	// JSON explanations and templates replace the regular report
+	var out io.Writer = os.Stdout
+	if explainJSON || tmplOpts.wanted() {
+		out = io.Discard
+	}
+
+	printer := output.NewPrinter(out, noColor, onlyConf)
+	printer.PrintSwooleHeader()
+
+	env, err := source.load(swooleWorkers, !noServices && reservedMemory == 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error detecting system info: %v\n", err)
+		os.Exit(1)
+	}
+	printer.PrintSystemInfo(env.System)
+	printer.PrintSwooleInfo(env.PHP)
+
+	opts := calculator.DefaultSwooleOptions()
+	opts.ReservedMemoryMB = reservedMemory
+	opts.WorkerMemoryMB = workerMemory
+	opts.Coroutine = coroutine
+	opts.TaskWorkers = taskWorkers
+	opts.TrafficProfile = profile
+
+	cfg := calculator.CalculateSwoole(*env, opts)
+	mustValidate(cfg.Validate())
+
+	if explainJSON {
+		if err := output.NewPrinter(os.Stdout, noColor, false).PrintExplanationJSON("swoole", cfg.Trace); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing explanation: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if tmplOpts.wanted() {
+		tmplOpts.render("swoole", *env, cfg, cfg.Directives(), nil, nil, cfg.Warnings, cfg.Recommendations, cfg.Trace)
+		return
+	}
+
+	printer.PrintSwooleCalculation(cfg)
+	if explain {
+		printer.PrintExplanation(cfg.Trace)
+	}
+	printer.PrintSwooleConfig(cfg)
+	printer.PrintSwooleWarnings(cfg)
+	printer.PrintSwooleRecommendations(cfg)
+	printer.PrintSwooleUsage()
+}
+
+func printSwooleUsage() {
+	fmt.Println(`Swoole / OpenSwoole Optimizer
+
+Sizes reactor, worker and task worker counts from the memory of the
+running worker processes.
+
+USAGE:
+    php-tuner swoole [options]
+    php-tuner openswoole [options]
+
+OPTIONS:
+    -h, --help          Show help
+    -c, --config-only   Output only configuration
+    --no-color          Disable colors
+    --traffic <name>    low, medium, high, bursty, api, batch, cron (default: medium)
+    --profiles <file>   JSON file with custom traffic profiles
+    --reserved <MB>     Reserved memory for OS/services
+    --worker-mem <MB>   Override worker memory (default: measured or 50MB)
+    --coroutine=false   Workers block on I/O (no coroutine hooks)
+    --task-workers      Size task workers for $server->task()
+    --no-services       Do not reserve memory for detected services
+                        (MySQL, PostgreSQL, Redis, Elasticsearch, ...)
+    --explain           Show every decision behind the configuration
+    --explain-format <f>  text or json (default: text)
+    --template <file>   Render the result through a Go text/template
+
+    --simulate-cpu <n>  Simulate a machine with n CPU cores (default: 2)
+    --simulate-mem <s>  Simulate a machine with this memory, e.g. 8G (default: 4G)
+    --system-from <f>   Read system information from a JSON file
+    --from-snapshot <f> Read system information from a snapshot
+
+EXAMPLES:
+    php-tuner swoole
+    php-tuner swoole --coroutine=false --task-workers
+    php-tuner swoole -c`)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/systemd"
+)
+
+// systemdOptions emits a unit drop-in that makes the kernel enforce the
+// calculated budget
+type systemdOptions struct {
+	enabled bool
+	unit    string
+	apply   bool
+}
+
+func (s *systemdOptions) register(fs *flag.FlagSet) {
+	fs.BoolVar(&s.enabled, "systemd", false, "Print a systemd drop-in with resource limits")
+	fs.StringVar(&s.unit, "unit", "", "systemd unit (default: auto-detect)")
+	fs.BoolVar(&s.apply, "apply", false, "Write the drop-in and run systemctl daemon-reload")
+}
+
+// wanted reports whether a drop-in is requested
+func (s *systemdOptions) wanted() bool {
+	return s.enabled || s.apply
+}
+
+// resolveUnit returns --unit or the installed unit. Limits for another
+// machine are never applied to this one.
+func (s *systemdOptions) resolveUnit(find func() (string, error), fallback string, live bool) (string, []string) {
+	if !s.wanted() {
+		return "", nil
+	}
+	if s.apply && !live {
+		fmt.Fprintln(os.Stderr, "Error: --apply writes limits for this host and cannot be combined with simulation or snapshots")
+		os.Exit(1)
+	}
+	if s.unit != "" {
+		return s.unit, nil
+	}
+	if !live {
+		return fallback, nil
+	}
+
+	unit, err := find()
+	if err != nil {
+		if s.apply {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --unit <name>)\n", err)
+			os.Exit(1)
+		}
+		return fallback, []string{fmt.Sprintf("No systemd unit found, showing the drop-in for %s (use --unit)", fallback)}
+	}
+	return unit, nil
+}
+
+// write installs the drop-in when --apply is set
+func (s *systemdOptions) write(unit string, cfg *calculator.SystemdConfig) {
+	if !s.apply {
+		return
+	}
+	path, err := systemd.Apply(unit, cfg.Directives())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying drop-in: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s and reloaded systemd\n", path)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/output"
+)
+
+// templateOptions renders the result through a user template instead of the
+// regular report
+type templateOptions struct {
+	path string
+	tmpl *template.Template
+}
+
+func (t *templateOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&t.path, "template", "", "Render the result through a text/template file")
+}
+
+// load parses the template, exiting on errors so they show before any output
+func (t *templateOptions) load() {
+	if t.path == "" {
+		return
+	}
+	tmpl, err := output.LoadTemplate(t.path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	t.tmpl = tmpl
+}
+
+// wanted reports whether the template replaces the report
+func (t *templateOptions) wanted() bool {
+	return t.tmpl != nil
+}
+
+// render writes the result of a runtime's calculation to stdout; ngx and
+// dropIn are nil unless they were requested
+func (t *templateOptions) render(runtime string, in calculator.Input, cfg any, directives []calculator.Directive,
+	ngx *calculator.NginxConfig, dropIn *calculator.SystemdConfig, warnings, recommendations []string, trace calculator.Trace) {
+	result := output.Result{
+		Input:           in,
+		Runtime:         runtime,
+		Config:          cfg,
+		Directives:      directives,
+		Nginx:           ngx,
+		Systemd:         dropIn,
+		Warnings:        warnings,
+		Recommendations: recommendations,
+		Trace:           trace,
+	}
+	if err := output.NewPrinter(os.Stdout, true, true).PrintTemplate(t.tmpl, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering %s: %v\n", t.path, err)
+		os.Exit(1)
+	}
+}
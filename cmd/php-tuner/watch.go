@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/watch"
+)
+
+func runWatch(args []string) {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+
+	var (
+		showHelp       bool
+		runtime        string
+		interval       time.Duration
+		outputs        string
+		statusURL      string
+		current        string
+		pool           string
+		threshold      float64
+		pmType         string
+		traffic        trafficOptions
+		reservedMemory int
+		processMemory  float64
+		threadMemory   float64
+		noServices     bool
+	)
+
+	fs.BoolVar(&showHelp, "help", false, "")
+	fs.BoolVar(&showHelp, "h", false, "")
+	fs.StringVar(&runtime, "runtime", watch.RuntimeFPM, "")
+	fs.DurationVar(&interval, "interval", 30*time.Second, "")
+	fs.StringVar(&outputs, "output", "stdout", "")
+	fs.StringVar(&statusURL, "status-url", "", "")
+	fs.StringVar(&current, "current", "", "")
+	fs.StringVar(&pool, "pool", "", "")
+	fs.Float64Var(&threshold, "drift-threshold", 20, "")
+	fs.StringVar(&pmType, "pm", "", "")
+	traffic.register(fs)
+	fs.IntVar(&reservedMemory, "reserved", 0, "")
+	fs.Float64Var(&processMemory, "process-mem", 0, "")
+	fs.Float64Var(&threadMemory, "thread-mem", 0, "")
+	fs.BoolVar(&noServices, "no-services", false, "")
+
+	fs.Usage = func() { printWatchUsage() }
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	if showHelp {
+		printWatchUsage()
+		return
+	}
+
+	profile := traffic.resolve()
+	pm := parsePMType(pmType)
+
+	cfg := watch.Config{
+		Interval:       interval,
+		StatusURL:      statusURL,
+		Current:        current,
+		Pool:           pool,
+		DriftThreshold: threshold,
+	}
+
+	// Services are detected once, they rarely change while watching
+	if !noServices && reservedMemory == 0 {
+		cfg.Services = detectServices()
+	}
+
+	switch strings.ToLower(runtime) {
+	case "php-fpm", "fpm":
+		cfg.Runtime = watch.RuntimeFPM
+		cfg.FPM = calculator.DefaultOptions()
+		cfg.FPM.ReservedMemoryMB = reservedMemory
+		cfg.FPM.ProcessMemoryMB = processMemory
+		cfg.FPM.TrafficProfile = profile
+		cfg.FPM.PMType = pm
+		if limit, err := php.GetPHPMemoryLimit(context.Background()); err == nil {
+			cfg.MemoryLimitMB = limit
+		}
+	case "frankenphp", "f":
+		cfg.Runtime = watch.RuntimeFrankenPHP
+		cfg.FrankenPHP = calculator.DefaultFrankenPHPOptions()
+		cfg.FrankenPHP.ReservedMemoryMB = reservedMemory
+		cfg.FrankenPHP.ThreadMemoryMB = threadMemory
+		cfg.FrankenPHP.TrafficProfile = profile
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown runtime: %s (expected php-fpm or frankenphp)\n", runtime)
+		os.Exit(1)
+	}
+
+	if interval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --interval must be positive")
+		os.Exit(1)
+	}
+
+	sinks, err := watch.NewSinks(outputs, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := watch.New(cfg, sinks).Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printWatchUsage() {
+	fmt.Println(`Watch Daemon
+
+Periodically re-detects the system and PHP processes, recomputes the
+recommendation and emits JSON events when:
+
+    max_children_reached  the FPM status page counter increased
+    memory_over_budget    PHP processes use more than the memory budget
+    drift                 the deployed config diverges from the recommendation
+    error                 a check failed (e.g. status page unreachable)
+
+USAGE:
+    php-tuner watch [options]
+
+OPTIONS:
+    -h, --help              Show help
+    --runtime <name>        php-fpm or frankenphp (default: php-fpm)
+    --interval <duration>   Time between checks (default: 30s)
+    --output <list>         Comma separated: stdout, syslog, webhook URL
+                            (default: stdout)
+    --status-url <url>      PHP-FPM status page URL (pm.status_path)
+    --current <file>        Deployed pool file or Caddyfile for drift checks
+    --pool <name>           Pool section to compare (default: first pool)
+    --drift-threshold <pct> Drift in percent that emits an event (default: 20)
+
+    --traffic, --profiles, --pm, --reserved, --process-mem, --thread-mem, --no-services
+                            Same as the php-fpm and frankenphp commands
+
+EXAMPLES:
+    php-tuner watch --status-url http://127.0.0.1/fpm-status \
+        --current /etc/php/8.3/fpm/pool.d/www.conf
+    php-tuner watch --runtime frankenphp --current /etc/frankenphp/Caddyfile \
+        --output syslog,https://hooks.example.com/php-tuner`)
+}
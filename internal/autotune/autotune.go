@@ -0,0 +1,482 @@
+package autotune
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Runtimes supported by the tuner
+const (
+	RuntimeFPM        = "php-fpm"
+	RuntimeFrankenPHP = "frankenphp"
+)
+
+// Config controls what the tuner changes, how it observes the result and
+// which SLOs decide whether a step is kept
+type Config struct {
+	Runtime    string
+	ConfigPath string   // Pool file or Caddyfile to rewrite
+	Pool       string   // PHP-FPM pool section (default: first pool)
+	StatePath  string   // JSON history, resumed on restart
+	Reload     []string // Command that reloads the runtime after a change
+
+	StatusURL       string // PHP-FPM status page
+	PrometheusURL   string
+	SaturationQuery string // Busy workers, overrides the status page
+	LatencyQuery    string // Request latency in milliseconds
+
+	Step           int     // Workers added or removed per step
+	Min            int     // Lowest setting to step down to
+	Max            int     // Highest setting, 0 = calculator's memory-safe maximum
+	HighSaturation float64 // Peak saturation in % that triggers a step up
+	LowSaturation  float64 // Peak saturation in % below which to step down
+	MaxLatencyMS   float64 // Latency SLO, 0 disables
+	MaxListenQueue int     // Listen queue tolerated after a step down
+	MaxSteps       int     // Stop after this many changes, 0 = run until cancelled
+
+	Soak           time.Duration // Observation period before and after each step
+	SampleInterval time.Duration
+
+	FPM           calculator.Options
+	FrankenPHP    calculator.FrankenPHPOptions
+	Services      []services.Service // Detected once at startup
+	MemoryLimitMB int                // PHP memory_limit (0 = unknown)
+
+	Log io.Writer
+}
+
+// Tuner adjusts one directive in small steps and keeps or reverts each step
+type Tuner struct {
+	cfg        Config
+	budget     int
+	minSetting int
+	maxSetting int
+}
+
+// New creates a tuner
+func New(cfg Config) *Tuner {
+	return &Tuner{cfg: cfg}
+}
+
+// Directive returns the directive the tuner adjusts
+func (t *Tuner) Directive() string {
+	if t.cfg.Runtime == RuntimeFrankenPHP {
+		return "num_threads"
+	}
+	return "pm.max_children"
+}
+
+// Run resumes an interrupted step, then observes, steps and judges until ctx
+// is done or MaxSteps changes were made
+func (t *Tuner) Run(ctx context.Context) error {
+	state, err := LoadState(t.cfg.StatePath)
+	if err != nil {
+		return err
+	}
+
+	if state.Config == "" {
+		state.Runtime, state.Config, state.Directive = t.cfg.Runtime, t.cfg.ConfigPath, t.Directive()
+	} else if state.Config != t.cfg.ConfigPath || state.Directive != t.Directive() {
+		return fmt.Errorf("state file %s tunes %s in %s, not %s in %s",
+			t.cfg.StatePath, state.Directive, state.Config, t.Directive(), t.cfg.ConfigPath)
+	}
+
+	if err := t.recalculate(ctx); err != nil {
+		return err
+	}
+
+	if step := state.Pending(); step != nil {
+		if err := t.resume(ctx, state, step); err != nil {
+			return err
+		}
+	}
+
+	for changes := 0; t.cfg.MaxSteps == 0 || changes < t.cfg.MaxSteps; {
+		if err := t.recalculate(ctx); err != nil {
+			return err
+		}
+
+		current, err := t.read()
+		if err != nil {
+			return err
+		}
+
+		t.logf("observing %s=%d for %s", t.Directive(), current, t.cfg.Soak)
+		before, err := t.observe(ctx, current)
+		if err != nil {
+			return ignoreCancel(err)
+		}
+
+		target, reason := t.decide(current, before)
+		if target == current {
+			t.logf("holding %s=%d: %s", t.Directive(), current, reason)
+			continue
+		}
+		if _, err := t.plan(target); err != nil {
+			// Invalid configs are never written, the next observation decides again
+			t.logf("holding %s=%d: %d is rejected: %v", t.Directive(), current, target, err)
+			continue
+		}
+
+		state.Steps = append(state.Steps, Step{
+			Started:   time.Now().UTC(),
+			Directive: t.Directive(),
+			From:      current,
+			To:        target,
+			Outcome:   OutcomePending,
+			Reason:    reason,
+			Before:    before,
+		})
+		step := &state.Steps[len(state.Steps)-1]
+		if err := state.Save(t.cfg.StatePath); err != nil {
+			return err
+		}
+
+		t.logf("setting %s %d -> %d: %s", t.Directive(), current, target, reason)
+		if err := t.apply(target); err != nil {
+			// Leave the runtime on the last known good value
+			if restoreErr := t.apply(current); restoreErr != nil {
+				err = errors.Join(err, restoreErr)
+			}
+			t.finish(state, step, nil, OutcomeAbandoned, "apply failed: "+err.Error())
+			return err
+		}
+
+		if err := t.judge(ctx, state, step); err != nil {
+			return ignoreCancel(err)
+		}
+		changes++
+	}
+
+	return nil
+}
+
+// resume continues a step whose soak was interrupted
+func (t *Tuner) resume(ctx context.Context, state *State, step *Step) error {
+	current, err := t.read()
+	if err != nil {
+		return err
+	}
+
+	switch current {
+	case step.To:
+		t.logf("resuming soak of %s %d -> %d", step.Directive, step.From, step.To)
+		return ignoreCancel(t.judge(ctx, state, step))
+	case step.From:
+		return t.finish(state, step, nil, OutcomeAbandoned, "change was not applied before the interruption")
+	default:
+		return t.finish(state, step, nil, OutcomeAbandoned,
+			fmt.Sprintf("%s was changed to %d outside of autotune", step.Directive, current))
+	}
+}
+
+// judge soaks the new setting and keeps or reverts it
+func (t *Tuner) judge(ctx context.Context, state *State, step *Step) error {
+	after, err := t.observe(ctx, step.To)
+	if err != nil {
+		return err
+	}
+
+	violation := t.violation(step, after)
+	if violation == "" {
+		t.logf("keeping %s=%d", step.Directive, step.To)
+		return t.finish(state, step, &after, OutcomeKept, "")
+	}
+
+	t.logf("reverting %s to %d: %s", step.Directive, step.From, violation)
+	if err := t.apply(step.From); err != nil {
+		return errors.Join(t.finish(state, step, &after, OutcomePending, "revert failed: "+err.Error()), err)
+	}
+	return t.finish(state, step, &after, OutcomeReverted, violation)
+}
+
+func (t *Tuner) finish(state *State, step *Step, after *Observation, outcome, note string) error {
+	now := time.Now().UTC()
+	step.After = after
+	step.Outcome = outcome
+	if outcome != OutcomePending {
+		step.Finished = &now
+	}
+	if note != "" {
+		step.Reason += "; " + note
+	}
+	return state.Save(t.cfg.StatePath)
+}
+
+// decide picks the next setting from the observation before a step
+func (t *Tuner) decide(current int, obs Observation) (int, string) {
+	if obs.MemoryMB > float64(obs.BudgetMB) {
+		if current <= t.minSetting {
+			return current, fmt.Sprintf("PHP uses %.0f MB of the %d MB budget but already at the minimum",
+				obs.MemoryMB, obs.BudgetMB)
+		}
+		return max(current-t.cfg.Step, t.minSetting), fmt.Sprintf("PHP uses %.0f MB, above the %d MB budget",
+			obs.MemoryMB, obs.BudgetMB)
+	}
+
+	saturated := obs.MaxChildrenReached > 0 || obs.ListenQueue > 0 || obs.PeakSaturationPct >= t.cfg.HighSaturation
+	if saturated {
+		reason := fmt.Sprintf("saturated (peak %.0f%% busy, listen queue %d, max children reached +%d)",
+			obs.PeakSaturationPct, obs.ListenQueue, obs.MaxChildrenReached)
+		if current >= t.maxSetting {
+			return current, reason + fmt.Sprintf(" but at the memory-safe maximum of %d", t.maxSetting)
+		}
+		return min(current+t.cfg.Step, t.maxSetting), reason
+	}
+
+	if obs.PeakSaturationPct < t.cfg.LowSaturation && current > t.minSetting {
+		return max(current-t.cfg.Step, t.minSetting), fmt.Sprintf("underused (peak %.0f%% busy, below %.0f%%)",
+			obs.PeakSaturationPct, t.cfg.LowSaturation)
+	}
+
+	return current, fmt.Sprintf("peak %.0f%% busy is within %.0f-%.0f%%",
+		obs.PeakSaturationPct, t.cfg.LowSaturation, t.cfg.HighSaturation)
+}
+
+// violation returns why a step breaks an SLO, or "" when it can be kept
+func (t *Tuner) violation(step *Step, after Observation) string {
+	if after.MemoryMB > float64(after.BudgetMB) {
+		return fmt.Sprintf("PHP used %.0f MB, above the %d MB budget", after.MemoryMB, after.BudgetMB)
+	}
+
+	stepDown := step.To < step.From
+	if t.cfg.MaxLatencyMS > 0 && after.LatencyMS > t.cfg.MaxLatencyMS &&
+		(stepDown || after.LatencyMS > step.Before.LatencyMS) {
+		return fmt.Sprintf("latency %.0f ms exceeds the %.0f ms SLO", after.LatencyMS, t.cfg.MaxLatencyMS)
+	}
+
+	if stepDown && (after.MaxChildrenReached > 0 || after.ListenQueue > t.cfg.MaxListenQueue) {
+		return fmt.Sprintf("saturated after stepping down (listen queue %d, max children reached +%d)",
+			after.ListenQueue, after.MaxChildrenReached)
+	}
+
+	return ""
+}
+
+// recalculate refreshes the memory budget and the lowest and memory-safe
+// highest settings
+func (t *Tuner) recalculate(ctx context.Context) error {
+	sysInfo, err := system.Detect()
+	if err != nil {
+		return err
+	}
+
+	if t.minSetting, err = t.minimum(); err != nil {
+		return err
+	}
+
+	if t.cfg.Runtime == RuntimeFrankenPHP {
+		cfg := calculator.CalculateFrankenPHP(calculator.Input{System: sysInfo, Services: t.cfg.Services}, t.cfg.FrankenPHP)
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		t.budget, t.maxSetting = cfg.AvailableMemoryMB, cfg.MaxThreads
+	} else {
+		procs, err := php.DetectProcesses(ctx)
+		if err != nil {
+			return err
+		}
+		cfg := calculator.Calculate(calculator.Input{System: sysInfo, PHP: procs, Services: t.cfg.Services,
+			MemoryLimitMB: t.cfg.MemoryLimitMB}, t.cfg.FPM)
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		t.budget, t.maxSetting = cfg.AvailableMemoryMB, cfg.MaxChildren
+	}
+
+	if t.cfg.Max > 0 {
+		t.maxSetting = t.cfg.Max
+	}
+	return nil
+}
+
+// minimum returns the lowest setting to step down to; FrankenPHP needs a
+// thread beyond the deployed worker threads
+func (t *Tuner) minimum() (int, error) {
+	if t.cfg.Runtime != RuntimeFrankenPHP {
+		return t.cfg.Min, nil
+	}
+	current, err := deployed.ParseCaddyfile(t.cfg.ConfigPath)
+	if err != nil {
+		return 0, err
+	}
+	return max(t.cfg.Min, atoi(current["worker.num"])+1), nil
+}
+
+func (t *Tuner) processPattern() string {
+	if t.cfg.Runtime == RuntimeFrankenPHP {
+		return "frankenphp"
+	}
+	return "php-fpm|php[0-9]"
+}
+
+// read returns the deployed value of the tuned directive
+func (t *Tuner) read() (int, error) {
+	var (
+		current map[string]string
+		err     error
+	)
+	if t.cfg.Runtime == RuntimeFrankenPHP {
+		current, err = deployed.ParseCaddyfile(t.cfg.ConfigPath)
+	} else {
+		current, err = deployed.ParseFPMPool(t.cfg.ConfigPath, t.cfg.Pool)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(current[t.Directive()])
+	if err != nil {
+		return 0, fmt.Errorf("%s is not set to a number in %s", t.Directive(), t.cfg.ConfigPath)
+	}
+	return value, nil
+}
+
+// apply writes the directive with the settings depending on it and reloads
+// the runtime. Nothing is written when the resulting config is invalid.
+func (t *Tuner) apply(value int) error {
+	directives, err := t.plan(value)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range directives {
+		if t.cfg.Runtime == RuntimeFrankenPHP {
+			err = deployed.SetCaddyfileDirective(t.cfg.ConfigPath, d.Name, d.Value)
+		} else {
+			err = deployed.SetFPMDirective(t.cfg.ConfigPath, t.cfg.Pool, d.Name, d.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(t.cfg.Reload) == 0 {
+		return nil
+	}
+
+	out, err := exec.Command(t.cfg.Reload[0], t.cfg.Reload[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("reload %q: %w: %s", strings.Join(t.cfg.Reload, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// plan returns the directives to write for value, or why the resulting
+// config is invalid
+func (t *Tuner) plan(value int) ([]calculator.Directive, error) {
+	if t.cfg.Runtime == RuntimeFrankenPHP {
+		return t.planFrankenPHP(value)
+	}
+	return t.planFPM(value)
+}
+
+// planFPM returns pm.max_children and the spare server settings clamped
+// into it, which PHP-FPM refuses to start without
+func (t *Tuner) planFPM(value int) ([]calculator.Directive, error) {
+	current, err := deployed.ParseFPMPool(t.cfg.ConfigPath, t.cfg.Pool)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &calculator.Config{
+		PM:                      calculator.PMType(current["pm"]),
+		MaxChildren:             value,
+		MinSpareServers:         atoi(current["pm.min_spare_servers"]),
+		MaxSpareServers:         atoi(current["pm.max_spare_servers"]),
+		MaxRequests:             atoi(current["pm.max_requests"]),
+		ProcessIdleTimeout:      cmp.Or(current["pm.process_idle_timeout"], "10s"),
+		RequestTerminateTimeout: current["request_terminate_timeout"],
+		ListenBacklog:           atoi(current["listen.backlog"]),
+	}
+	// PHP-FPM's default start_servers
+	cfg.StartServers = cmp.Or(atoi(current["pm.start_servers"]),
+		cfg.MinSpareServers+(cfg.MaxSpareServers-cfg.MinSpareServers)/2)
+
+	directives := []calculator.Directive{{Name: "pm.max_children", Value: strconv.Itoa(value)}}
+	if cfg.PM == calculator.PMDynamic {
+		cfg.MaxSpareServers = min(cfg.MaxSpareServers, cfg.MaxChildren)
+		cfg.MinSpareServers = min(cfg.MinSpareServers, cfg.MaxSpareServers)
+		cfg.StartServers = max(min(cfg.StartServers, cfg.MaxSpareServers), cfg.MinSpareServers)
+
+		for _, d := range []calculator.Directive{
+			{Name: "pm.max_spare_servers", Value: strconv.Itoa(cfg.MaxSpareServers)},
+			{Name: "pm.min_spare_servers", Value: strconv.Itoa(cfg.MinSpareServers)},
+			{Name: "pm.start_servers", Value: strconv.Itoa(cfg.StartServers)},
+		} {
+			if deployedValue, ok := current[d.Name]; ok && deployedValue != d.Value {
+				directives = append(directives, d)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return directives, nil
+}
+
+// planFrankenPHP returns num_threads and max_threads raised to it; the
+// threads of the workers cannot be changed and must fit below num_threads
+func (t *Tuner) planFrankenPHP(value int) ([]calculator.Directive, error) {
+	current, err := deployed.ParseCaddyfile(t.cfg.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &calculator.FrankenPHPConfig{
+		NumThreads:  value,
+		MaxThreads:  atoi(current["max_threads"]), // 0 for auto
+		WorkerNum:   atoi(current["worker.num"]),
+		MaxWaitTime: current["max_wait_time"],
+	}
+	if cfg.WorkerNum > 0 {
+		cfg.Workers = []calculator.Worker{{File: current["worker.file"], Num: cfg.WorkerNum}}
+	}
+
+	directives := []calculator.Directive{{Name: "num_threads", Value: strconv.Itoa(value)}}
+	if cfg.MaxThreads > 0 && cfg.MaxThreads < value {
+		cfg.MaxThreads = value
+		directives = append(directives, calculator.Directive{Name: "max_threads", Value: strconv.Itoa(value)})
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return directives, nil
+}
+
+// atoi returns 0 for unset or non-numeric values
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func (t *Tuner) logf(format string, args ...any) {
+	if t.cfg.Log == nil {
+		return
+	}
+	fmt.Fprintf(t.cfg.Log, "%s %s\n", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+// ignoreCancel treats cancellation as a clean shutdown, pending steps are
+// resumed on the next run
+func ignoreCancel(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+package autotune
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApplyFPMClampsSpareServers(t *testing.T) {
+	path := writeFile(t, "www.conf", `[www]
+pm = dynamic
+pm.max_children = 20
+pm.start_servers = 8
+pm.min_spare_servers = 6
+pm.max_spare_servers = 12
+`)
+
+	tuner := New(Config{Runtime: RuntimeFPM, ConfigPath: path})
+	if err := tuner.apply(5); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	got, err := deployed.ParseFPMPool(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"pm.max_children":      "5",
+		"pm.max_spare_servers": "5",
+		"pm.min_spare_servers": "5",
+		"pm.start_servers":     "5",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestApplyFrankenPHP(t *testing.T) {
+	const caddyfile = `{
+	frankenphp {
+		num_threads 8
+		max_threads 10
+		worker /app/public/index.php 4
+	}
+}
+`
+
+	t.Run("raises max_threads", func(t *testing.T) {
+		path := writeFile(t, "Caddyfile", caddyfile)
+		if err := New(Config{Runtime: RuntimeFrankenPHP, ConfigPath: path}).apply(12); err != nil {
+			t.Fatalf("apply: %v", err)
+		}
+		got, err := deployed.ParseCaddyfile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got["num_threads"] != "12" || got["max_threads"] != "12" {
+			t.Errorf("num_threads %s, max_threads %s, want 12 and 12", got["num_threads"], got["max_threads"])
+		}
+	})
+
+	t.Run("rejects too few threads for the workers", func(t *testing.T) {
+		path := writeFile(t, "Caddyfile", caddyfile)
+		if err := New(Config{Runtime: RuntimeFrankenPHP, ConfigPath: path}).apply(3); err == nil {
+			t.Fatal("apply accepted num_threads below the worker threads")
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != caddyfile {
+			t.Errorf("Caddyfile was rewritten:\n%s", data)
+		}
+	})
+}
+
+func TestDecideKeepsThreadsForWorkers(t *testing.T) {
+	path := writeFile(t, "Caddyfile", `{
+	frankenphp {
+		num_threads 8
+		worker /app/public/index.php 4
+	}
+}
+`)
+
+	tuner := New(Config{Runtime: RuntimeFrankenPHP, ConfigPath: path, Min: 2, Step: 4,
+		LowSaturation: 40, HighSaturation: 85})
+	lowest, err := tuner.minimum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lowest != 5 {
+		t.Fatalf("minimum = %d, want 5 (worker threads + 1)", lowest)
+	}
+	tuner.minSetting, tuner.maxSetting = lowest, 16
+
+	tests := []struct {
+		name    string
+		current int
+		obs     Observation
+		want    int
+	}{
+		{"underused", 8, Observation{PeakSaturationPct: 10}, 5},
+		{"underused at the minimum", 5, Observation{PeakSaturationPct: 10}, 5},
+		{"over budget", 8, Observation{MemoryMB: 900, BudgetMB: 800}, 5},
+		{"over budget at the minimum", 5, Observation{MemoryMB: 900, BudgetMB: 800}, 5},
+	}
+	for _, tt := range tests {
+		if got, reason := tuner.decide(tt.current, tt.obs); got != tt.want {
+			t.Errorf("%s: decide(%d) = %d (%s), want %d", tt.name, tt.current, got, reason, tt.want)
+		}
+	}
+
+	// Without workers the configured minimum applies
+	path = writeFile(t, "Caddyfile", "{\n\tfrankenphp {\n\t\tnum_threads 8\n\t}\n}\n")
+	if lowest, err := New(Config{Runtime: RuntimeFrankenPHP, ConfigPath: path, Min: 2}).minimum(); err != nil || lowest != 2 {
+		t.Errorf("minimum without workers = %d, %v, want 2", lowest, err)
+	}
+}
@@ -0,0 +1,170 @@
+package autotune
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+// Observation summarises saturation, latency and memory over a soak period
+type Observation struct {
+	Samples            int     `json:"samples"`
+	SaturationPct      float64 `json:"saturation_pct"`      // Average busy workers in % of the setting
+	PeakSaturationPct  float64 `json:"peak_saturation_pct"` // Highest sampled saturation
+	ListenQueue        int     `json:"listen_queue"`        // Highest sampled listen queue (PHP-FPM)
+	MaxChildrenReached int64   `json:"max_children_reached"`
+	LatencyMS          float64 `json:"latency_ms,omitempty"` // From the latency query, 0 = not measured
+	MemoryMB           float64 `json:"memory_mb"`            // Highest sampled PHP memory
+	BudgetMB           int     `json:"budget_mb"`
+}
+
+// sample is a single reading taken during the soak
+type sample struct {
+	saturationPct      float64
+	listenQueue        int
+	maxChildrenReached int64
+	memoryMB           float64
+}
+
+// observe samples the runtime every interval for the soak duration
+func (t *Tuner) observe(ctx context.Context, setting int) (Observation, error) {
+	obs := Observation{BudgetMB: t.budget}
+	var first, last *sample
+	total := 0.0
+
+	deadline := time.Now().Add(t.cfg.Soak)
+	for {
+		s, err := t.sample(ctx, setting)
+		if err != nil {
+			return obs, err
+		}
+
+		if first == nil {
+			first = &s
+		}
+		last = &s
+		obs.Samples++
+		total += s.saturationPct
+		obs.PeakSaturationPct = max(obs.PeakSaturationPct, s.saturationPct)
+		obs.ListenQueue = max(obs.ListenQueue, s.listenQueue)
+		obs.MemoryMB = max(obs.MemoryMB, s.memoryMB)
+
+		if !time.Now().Add(t.cfg.SampleInterval).Before(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return obs, ctx.Err()
+		case <-time.After(t.cfg.SampleInterval):
+		}
+	}
+
+	obs.SaturationPct = total / float64(obs.Samples)
+	obs.MaxChildrenReached = last.maxChildrenReached - first.maxChildrenReached
+
+	if t.cfg.LatencyQuery != "" {
+		latency, err := QueryPrometheus(t.cfg.PrometheusURL, t.cfg.LatencyQuery)
+		if err != nil {
+			return obs, fmt.Errorf("latency query: %w", err)
+		}
+		obs.LatencyMS = latency
+	}
+
+	return obs, nil
+}
+
+func (t *Tuner) sample(ctx context.Context, setting int) (sample, error) {
+	var s sample
+
+	procs, err := php.DetectProcessesMatching(ctx, t.processPattern())
+	if err != nil {
+		return s, err
+	}
+	s.memoryMB = procs.TotalMemMB
+
+	switch {
+	case t.cfg.SaturationQuery != "":
+		busy, err := QueryPrometheus(t.cfg.PrometheusURL, t.cfg.SaturationQuery)
+		if err != nil {
+			return s, fmt.Errorf("saturation query: %w", err)
+		}
+		s.saturationPct = busy * 100 / float64(max(setting, 1))
+	case t.cfg.StatusURL != "":
+		status, err := php.FetchStatus(t.cfg.StatusURL)
+		if err != nil {
+			return s, fmt.Errorf("status page: %w", err)
+		}
+		s.saturationPct = float64(status.ActiveProcesses) * 100 / float64(max(setting, 1))
+		s.listenQueue = status.ListenQueue
+		s.maxChildrenReached = status.MaxChildrenReached
+	}
+
+	return s, nil
+}
+
+// QueryPrometheus evaluates an instant query and returns the first value
+func QueryPrometheus(baseURL, query string) (float64, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/api/v1/query")
+	if err != nil {
+		return 0, fmt.Errorf("invalid Prometheus URL: %w", err)
+	}
+	u.RawQuery = url.Values{"query": {query}}.Encode()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to parse Prometheus response: %w", err)
+	}
+	if body.Status != "success" {
+		return 0, fmt.Errorf("Prometheus query failed: %s", body.Error)
+	}
+
+	// Scalars are [ts, "v"], vectors are [{"value": [ts, "v"]}, ...]
+	var value []any
+	switch body.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(body.Data.Result, &value); err != nil {
+			return 0, err
+		}
+	case "vector":
+		var vector []struct {
+			Value []any `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &vector); err != nil {
+			return 0, err
+		}
+		if len(vector) == 0 {
+			return 0, fmt.Errorf("query %q returned no series", query)
+		}
+		value = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", body.Data.ResultType)
+	}
+
+	if len(value) != 2 {
+		return 0, fmt.Errorf("unexpected sample in response to %q", query)
+	}
+	raw, _ := value[1].(string)
+	return strconv.ParseFloat(raw, 64)
+}
@@ -0,0 +1,103 @@
+package autotune
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// StateVersion is the current state file format
+const StateVersion = 1
+
+// Step outcomes
+const (
+	OutcomePending   = "pending"
+	OutcomeKept      = "kept"
+	OutcomeReverted  = "reverted"
+	OutcomeAbandoned = "abandoned"
+)
+
+// State is persisted after every change so the tuner can resume and every
+// step can be audited
+type State struct {
+	Version   int    `json:"version"`
+	Runtime   string `json:"runtime"`
+	Config    string `json:"config"`
+	Directive string `json:"directive"`
+	Steps     []Step `json:"steps"`
+}
+
+// Step records a single change and the observations that decided its fate
+type Step struct {
+	Started   time.Time    `json:"started"`
+	Finished  *time.Time   `json:"finished,omitempty"`
+	Directive string       `json:"directive"`
+	From      int          `json:"from"`
+	To        int          `json:"to"`
+	Outcome   string       `json:"outcome"`
+	Reason    string       `json:"reason"`
+	Before    Observation  `json:"before"`
+	After     *Observation `json:"after,omitempty"`
+}
+
+// Pending returns the step that was interrupted during its soak, if any
+func (s *State) Pending() *Step {
+	if len(s.Steps) == 0 {
+		return nil
+	}
+	last := &s.Steps[len(s.Steps)-1]
+	if last.Outcome != OutcomePending {
+		return nil
+	}
+	return last
+}
+
+// LoadState reads a state file; a missing file yields an empty state
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &State{Version: StateVersion}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if state.Version > StateVersion {
+		return nil, fmt.Errorf("state file %s has version %d, newer than supported %d",
+			path, state.Version, StateVersion)
+	}
+
+	return state, nil
+}
+
+// Save writes the state atomically
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,298 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/fastcgi"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+// Config describes a load test ramp
+type Config struct {
+	FastCGI      string   // PHP-FPM address, "127.0.0.1:9000" or "unix:/run/php/php-fpm.sock"
+	URL          string   // Base URL for HTTP targets such as FrankenPHP
+	DocumentRoot string   // Prefix for SCRIPT_FILENAME in FastCGI mode
+	Scripts      []string // Script paths requested round-robin
+	Ramp         []int    // Concurrency levels, one step each
+	StepDuration time.Duration
+	Timeout      time.Duration
+	PlateauGain  float64 // Minimum throughput gain in percent to keep ramping
+	MaxErrorRate float64 // Error rate in percent that ends the ramp
+	ProcessMatch string  // Pattern for worker memory sampling (empty disables)
+}
+
+// Step holds the measurements of one concurrency level
+type Step struct {
+	Concurrency int
+	Requests    int64
+	Errors      int64
+	Throughput  float64 // Successful requests per second
+	P50         time.Duration
+	P90         time.Duration
+	P99         time.Duration
+	WorkerMemMB float64 // Peak memory of all workers during the step
+	WorkerCount int
+}
+
+// ErrorRate returns the share of failed requests in percent
+func (s Step) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Requests) * 100
+}
+
+// Result is the outcome of a full ramp
+type Result struct {
+	Steps []Step
+
+	// Plateau is the concurrency after which throughput stopped improving,
+	// 0 when throughput kept growing until the end of the ramp
+	Plateau        int
+	PeakThroughput float64
+}
+
+// requester performs a single request and reports whether it succeeded
+type requester func(ctx context.Context, script string) error
+
+// Run executes the concurrency ramp until it ends, errors exceed the limit or
+// throughput plateaus
+func Run(ctx context.Context, cfg Config) (*Result, error) {
+	return RunWithProgress(ctx, cfg, nil)
+}
+
+// RunWithProgress is Run with a callback invoked after every step
+func RunWithProgress(ctx context.Context, cfg Config, progress func(Step)) (*Result, error) {
+	do, err := newRequester(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, concurrency := range cfg.Ramp {
+		if ctx.Err() != nil {
+			break
+		}
+
+		step := runStep(ctx, cfg, do, concurrency)
+		result.Steps = append(result.Steps, step)
+		if progress != nil {
+			progress(step)
+		}
+
+		if step.Requests == 0 || step.Throughput == 0 || step.ErrorRate() > cfg.MaxErrorRate {
+			// Failing requests mean the previous level was the usable maximum
+			if len(result.Steps) > 1 {
+				result.Plateau = result.Steps[len(result.Steps)-2].Concurrency
+			}
+			break
+		}
+
+		if step.Throughput > result.PeakThroughput {
+			gain := 100.0
+			if result.PeakThroughput > 0 {
+				gain = (step.Throughput - result.PeakThroughput) / result.PeakThroughput * 100
+			}
+			result.PeakThroughput = step.Throughput
+			if gain >= cfg.PlateauGain {
+				continue
+			}
+		}
+
+		// The first step has nothing to compare with, no plateau yet
+		if len(result.Steps) == 1 {
+			continue
+		}
+
+		// Throughput no longer improves: the previous level saturated the workers
+		result.Plateau = result.Steps[len(result.Steps)-2].Concurrency
+		break
+	}
+
+	if len(result.Steps) == 0 {
+		return nil, errors.New("benchmark was interrupted before the first step")
+	}
+
+	return result, nil
+}
+
+func runStep(ctx context.Context, cfg Config, do requester, concurrency int) Step {
+	ctx, cancel := context.WithTimeout(ctx, cfg.StepDuration)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		latencies []time.Duration
+		requests  atomic.Int64
+		failures  atomic.Int64
+		next      atomic.Int64
+	)
+
+	step := Step{Concurrency: concurrency}
+
+	stopSampling := sampleMemory(ctx, cfg.ProcessMatch, &step)
+
+	start := time.Now()
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				script := cfg.Scripts[int(next.Add(1))%len(cfg.Scripts)]
+
+				began := time.Now()
+				err := do(ctx, script)
+				elapsed := time.Since(began)
+
+				// Requests cut off by the end of the step are not counted
+				if ctx.Err() != nil {
+					return
+				}
+
+				requests.Add(1)
+				if err != nil {
+					failures.Add(1)
+					continue
+				}
+
+				mu.Lock()
+				latencies = append(latencies, elapsed)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	stopSampling()
+
+	step.Requests = requests.Load()
+	step.Errors = failures.Load()
+	step.Throughput = float64(len(latencies)) / time.Since(start).Seconds()
+
+	slices.Sort(latencies)
+	step.P50 = percentile(latencies, 50)
+	step.P90 = percentile(latencies, 90)
+	step.P99 = percentile(latencies, 99)
+
+	return step
+}
+
+// sampleMemory records the peak worker memory once per second until stopped
+func sampleMemory(ctx context.Context, pattern string, step *Step) func() {
+	if pattern == "" {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			if info, err := php.DetectProcessesMatching(ctx, pattern); err == nil && info.TotalMemMB > step.WorkerMemMB {
+				step.WorkerMemMB = info.TotalMemMB
+				step.WorkerCount = info.ProcessCount
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p + 99) / 100
+	return sorted[max(idx-1, 0)]
+}
+
+func newRequester(cfg Config) (requester, error) {
+	if len(cfg.Scripts) == 0 {
+		return nil, errors.New("no scripts to request")
+	}
+
+	switch {
+	case cfg.FastCGI != "":
+		client := fastcgi.NewClient(cfg.FastCGI, cfg.Timeout)
+		return func(ctx context.Context, script string) error {
+			resp, err := client.Do(ctx, fastCGIParams(cfg.DocumentRoot, script), nil)
+			if err != nil {
+				return err
+			}
+			if resp.Status >= 400 {
+				return fmt.Errorf("status %d", resp.Status)
+			}
+			return nil
+		}, nil
+
+	case cfg.URL != "":
+		client := &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{MaxIdleConnsPerHost: slices.Max(cfg.Ramp)},
+		}
+		base := strings.TrimRight(cfg.URL, "/")
+		return func(ctx context.Context, script string) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/"+strings.TrimLeft(script, "/"), nil)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			_, _ = io.Copy(io.Discard, resp.Body)
+			if resp.StatusCode >= 400 {
+				return fmt.Errorf("status %d", resp.StatusCode)
+			}
+			return nil
+		}, nil
+	}
+
+	return nil, errors.New("either a FastCGI address or a URL is required")
+}
+
+// fastCGIParams builds the CGI environment nginx would send for a GET request
+func fastCGIParams(docRoot, script string) map[string]string {
+	uri := "/" + strings.TrimLeft(script, "/")
+	scriptName, query, _ := strings.Cut(uri, "?")
+
+	return map[string]string{
+		"GATEWAY_INTERFACE": "CGI/1.1",
+		"SERVER_SOFTWARE":   "php-tuner",
+		"SERVER_PROTOCOL":   "HTTP/1.1",
+		"SERVER_NAME":       "localhost",
+		"SERVER_PORT":       "80",
+		"REMOTE_ADDR":       "127.0.0.1",
+		"REQUEST_METHOD":    http.MethodGet,
+		"REQUEST_URI":       uri,
+		"QUERY_STRING":      query,
+		"DOCUMENT_ROOT":     docRoot,
+		"SCRIPT_NAME":       scriptName,
+		"SCRIPT_FILENAME":   path.Join(docRoot, scriptName),
+		"CONTENT_LENGTH":    "0",
+	}
+}
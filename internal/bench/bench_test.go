@@ -0,0 +1,55 @@
+package bench
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRunFirstStep(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name        string
+		url         string
+		plateauGain float64
+		maxErrors   float64
+		minSteps    int
+		maxSteps    int
+	}{
+		// No gain can reach 101%, the first step still needs a second to compare with
+		{"unreachable gain", ok.URL, 101, 1, 2, 3},
+		// Every request fails but the error limit is never exceeded
+		{"all errors", failing.URL, 5, 100, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Run(context.Background(), Config{
+				URL:          tt.url,
+				Scripts:      []string{"/"},
+				Ramp:         []int{1, 2, 4},
+				StepDuration: 50 * time.Millisecond,
+				Timeout:      time.Second,
+				PlateauGain:  tt.plateauGain,
+				MaxErrorRate: tt.maxErrors,
+			})
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if n := len(result.Steps); n < tt.minSteps || n > tt.maxSteps {
+				t.Errorf("ran %d steps, want %d to %d", n, tt.minSteps, tt.maxSteps)
+			}
+			if len(result.Steps) == 1 && result.Plateau != 0 {
+				t.Errorf("plateau %d without a previous step", result.Plateau)
+			}
+		})
+	}
+}
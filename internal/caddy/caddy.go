@@ -0,0 +1,115 @@
+package caddy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// AppPath is the admin API path of the frankenphp app
+const AppPath = "/config/apps/frankenphp"
+
+// App is the JSON config of the frankenphp Caddy app
+type App struct {
+	NumThreads  int      `json:"num_threads"`
+	MaxThreads  int      `json:"max_threads,omitempty"`
+	MaxWaitTime int64    `json:"max_wait_time,omitempty"` // Nanoseconds, like every Caddy duration
+	Workers     []Worker `json:"workers,omitempty"`
+}
+
+// Worker is a worker script of the frankenphp app
+type Worker struct {
+	FileName   string            `json:"file_name"`
+	Num        int               `json:"num,omitempty"`
+	MaxThreads int               `json:"max_threads,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// Config is a full Caddy config holding only the frankenphp app, as
+// loaded with POST /load
+type Config struct {
+	Apps struct {
+		FrankenPHP App `json:"frankenphp"`
+	} `json:"apps"`
+}
+
+// NewApp converts a FrankenPHP configuration, workers without a script use
+// defaultFile
+func NewApp(cfg *calculator.FrankenPHPConfig, workerMode bool, defaultFile string) (App, error) {
+	app := App{NumThreads: cfg.NumThreads}
+
+	if cfg.MaxThreads > cfg.NumThreads {
+		app.MaxThreads = cfg.MaxThreads
+	}
+
+	if cfg.MaxWaitTime != "" {
+		wait, err := calculator.ParseDuration(cfg.MaxWaitTime)
+		if err != nil {
+			return app, fmt.Errorf("invalid max_wait_time %q: %w", cfg.MaxWaitTime, err)
+		}
+		app.MaxWaitTime = wait.Nanoseconds()
+	}
+
+	if !workerMode {
+		return app, nil
+	}
+	for _, w := range cfg.Workers {
+		worker := Worker{FileName: w.File, Num: w.Num}
+		if worker.FileName == "" {
+			worker.FileName = defaultFile
+		}
+		if w.MaxThreads > w.Num {
+			worker.MaxThreads = w.MaxThreads
+		}
+		if len(w.Env) > 0 {
+			worker.Env = make(map[string]string, len(w.Env))
+			for _, env := range w.Env {
+				worker.Env[env.Name] = env.Value
+			}
+		}
+		app.Workers = append(app.Workers, worker)
+	}
+	return app, nil
+}
+
+// NewConfig wraps the app into a full Caddy config
+func NewConfig(app App) Config {
+	var cfg Config
+	cfg.Apps.FrankenPHP = app
+	return cfg
+}
+
+// Patch replaces the frankenphp app of a running Caddy through its admin
+// API, e.g. http://localhost:2019. Caddy rolls back configs it cannot load.
+func Patch(adminURL string, app App) error {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(adminURL, "/") + AppPath
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("PATCH %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package caddy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+func TestPatch(t *testing.T) {
+	var got App
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("method = %s, want PATCH", r.Method)
+		}
+		if r.URL.Path != AppPath {
+			t.Errorf("path = %s, want %s", r.URL.Path, AppPath)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("content type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	app := App{
+		NumThreads:  8,
+		MaxThreads:  16,
+		MaxWaitTime: int64(10 * time.Second),
+		Workers:     []Worker{{FileName: "/app/public/index.php", Num: 7, Env: map[string]string{"APP_ENV": "prod"}}},
+	}
+	// A trailing slash must not produce a double slash in the path
+	if err := Patch(srv.URL+"/", app); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if !reflect.DeepEqual(got, app) {
+		t.Errorf("received %+v, want %+v", got, app)
+	}
+}
+
+func TestPatchError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "loading new config: invalid worker", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := Patch(srv.URL, App{NumThreads: 4})
+	if err == nil {
+		t.Fatal("Patch succeeded, want an error for status 400")
+	}
+	if !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "invalid worker") {
+		t.Errorf("error %q does not name the status and response", err)
+	}
+}
+
+func TestNewAppMaxWaitTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"30s", 30 * time.Second},
+		{"5m", 5 * time.Minute},
+		{"1d", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		app, err := NewApp(&calculator.FrankenPHPConfig{NumThreads: 4, MaxWaitTime: tt.value}, false, "")
+		if err != nil {
+			t.Errorf("NewApp(%q): %v", tt.value, err)
+			continue
+		}
+		if app.MaxWaitTime != tt.want.Nanoseconds() {
+			t.Errorf("NewApp(%q).MaxWaitTime = %d, want %d", tt.value, app.MaxWaitTime, tt.want.Nanoseconds())
+		}
+	}
+
+	if _, err := NewApp(&calculator.FrankenPHPConfig{NumThreads: 4, MaxWaitTime: "10 sec"}, false, ""); err == nil {
+		t.Error("NewApp accepted max_wait_time \"10 sec\"")
+	}
+}
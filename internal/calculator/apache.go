@@ -0,0 +1,165 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// ApacheConfig holds the calculated Apache prefork MPM configuration
+type ApacheConfig struct {
+	StartServers           int
+	MinSpareServers        int
+	MaxSpareServers        int
+	ServerLimit            int
+	MaxRequestWorkers      int
+	MaxConnectionsPerChild int
+
+	// Metadata for display
+	ReservedMemoryMB  int
+	ReservedBreakdown []ReservedItem
+	AvailableMemoryMB int
+	ProcessMemoryMB   float64
+	Warnings          []string
+	Recommendations   []string
+	Trace             Trace
+}
+
+// ApacheOptions for calculation
+type ApacheOptions struct {
+	ReservedMemoryMB int     // Memory reserved for OS/other services
+	ProcessMemoryMB  float64 // Override detected child memory
+	TrafficProfile   Profile // Expected traffic pattern
+}
+
+// DefaultApacheOptions returns sensible defaults
+func DefaultApacheOptions() ApacheOptions {
+	return ApacheOptions{
+		ReservedMemoryMB: 0, // Auto-calculate
+		ProcessMemoryMB:  0, // Auto-detect
+		TrafficProfile:   DefaultProfile(),
+	}
+}
+
+// CalculateApache computes prefork MPM settings for Apache with mod_php,
+// where every child embeds its own PHP interpreter
+func CalculateApache(in Input, opts ApacheOptions) *ApacheConfig {
+	cfg := &ApacheConfig{
+		Warnings:        []string{},
+		Recommendations: []string{},
+	}
+	trace := &cfg.Trace
+	sysInfo := in.System
+
+	// The PHP-FPM memory model applies per child, mod_php children just
+	// carry the Apache modules on top
+	fpmOpts := Options{ReservedMemoryMB: opts.ReservedMemoryMB}
+
+	var memSource string
+	cfg.ProcessMemoryMB, memSource = determineProcessMemory(in, opts.ProcessMemoryMB)
+	if cfg.ProcessMemoryMB == 0 {
+		cfg.ProcessMemoryMB = 80 // Assume 64MB PHP plus Apache modules
+		memSource = "80 MB estimate"
+		cfg.Warnings = append(cfg.Warnings, "Could not detect Apache child memory, using 80MB estimate")
+	}
+	trace.set("process_memory", "child memory source", memSource,
+		fmt.Sprintf("%.1f MB", cfg.ProcessMemoryMB), "Memory one Apache child with mod_php is expected to use")
+
+	cfg.ReservedMemoryMB, cfg.ReservedBreakdown = determineReservedMemory(in, fpmOpts)
+	trace.set("reserved_memory", "sum of reserved items", formatReserved(cfg.ReservedBreakdown),
+		fmt.Sprintf("%d MB", cfg.ReservedMemoryMB), "Memory kept free for the OS and other services")
+
+	cfg.AvailableMemoryMB = sysInfo.MemTotalMB - cfg.ReservedMemoryMB
+	trace.set("available_memory", "total - reserved",
+		fmt.Sprintf("%d MB - %d MB", sysInfo.MemTotalMB, cfg.ReservedMemoryMB),
+		fmt.Sprintf("%d MB", cfg.AvailableMemoryMB), "Memory budget for all Apache children")
+	if cfg.AvailableMemoryMB < 256 {
+		trace.adjust("available_memory", "minimum 256 MB", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB),
+			"256 MB", "Reservation leaves too little memory, falling back to the minimum")
+		cfg.AvailableMemoryMB = 256
+		cfg.Warnings = append(cfg.Warnings, "Very low available memory, using minimum of 256MB")
+	}
+
+	cfg.MaxRequestWorkers = int(math.Floor(float64(cfg.AvailableMemoryMB) / cfg.ProcessMemoryMB))
+	trace.set("MaxRequestWorkers", "available / child memory",
+		fmt.Sprintf("%d MB / %.1f MB", cfg.AvailableMemoryMB, cfg.ProcessMemoryMB),
+		cfg.MaxRequestWorkers, "As many children as fit into the memory budget")
+
+	if cfg.MaxRequestWorkers < 5 {
+		trace.adjust("MaxRequestWorkers", "minimum 5", cfg.MaxRequestWorkers, 5,
+			"Too few children to serve concurrent requests")
+		cfg.MaxRequestWorkers = 5
+		cfg.Warnings = append(cfg.Warnings, "MaxRequestWorkers increased to minimum of 5")
+	}
+	if cfg.MaxRequestWorkers > 1000 {
+		trace.adjust("MaxRequestWorkers", "maximum 1000", cfg.MaxRequestWorkers, 1000,
+			"Context switching outweighs more children")
+		cfg.MaxRequestWorkers = 1000
+		cfg.Warnings = append(cfg.Warnings, "MaxRequestWorkers capped at 1000")
+	}
+
+	// Prefork silently caps MaxRequestWorkers at ServerLimit (default 256)
+	cfg.ServerLimit = cfg.MaxRequestWorkers
+	trace.set("ServerLimit", "= MaxRequestWorkers", "", cfg.ServerLimit,
+		"Prefork caps MaxRequestWorkers at ServerLimit")
+
+	cpuCores := max(sysInfo.CPUCores, 1)
+	cores := fmt.Sprintf("%d CPU cores", cpuCores)
+
+	// Forking a mod_php child is expensive, keep more spares for busier sites
+	spareFactor := opts.TrafficProfile.scale(2)
+	profile := fmt.Sprintf("%s, %s", cores, opts.TrafficProfile.Name)
+
+	cfg.StartServers = cpuCores * spareFactor
+	cfg.MinSpareServers = cpuCores * spareFactor
+	cfg.MaxSpareServers = cpuCores * spareFactor * 2
+	trace.set("StartServers", "CPU x traffic factor", profile, cfg.StartServers, "Children forked at startup")
+	trace.set("MinSpareServers", "CPU x traffic factor", profile, cfg.MinSpareServers, "Idle children kept ready")
+	trace.set("MaxSpareServers", "2 x MinSpareServers", profile, cfg.MaxSpareServers,
+		"Idle children before killing extras")
+
+	if cfg.MaxSpareServers > cfg.MaxRequestWorkers {
+		trace.adjust("MaxSpareServers", "<= MaxRequestWorkers", cfg.MaxSpareServers, cfg.MaxRequestWorkers,
+			"Cannot keep more idle children than MaxRequestWorkers")
+		cfg.MaxSpareServers = cfg.MaxRequestWorkers
+	}
+	if cfg.MinSpareServers >= cfg.MaxSpareServers {
+		trace.adjust("MinSpareServers", "< MaxSpareServers", cfg.MinSpareServers, cfg.MaxSpareServers/2,
+			"Apache raises MaxSpareServers to MinSpareServers + 1 otherwise")
+		cfg.MinSpareServers = cfg.MaxSpareServers / 2
+	}
+	if cfg.StartServers > cfg.MaxSpareServers {
+		trace.adjust("StartServers", "<= MaxSpareServers", cfg.StartServers, cfg.MaxSpareServers,
+			"Children above MaxSpareServers are killed right after startup")
+		cfg.StartServers = cfg.MaxSpareServers
+	}
+
+	// Keep-alive connections serve several requests each
+	cfg.MaxConnectionsPerChild = 1000
+	trace.set("MaxConnectionsPerChild", "fixed", "", cfg.MaxConnectionsPerChild,
+		"Recycle children to contain memory leaks")
+
+	addApacheRecommendations(cfg, sysInfo)
+
+	advice := adviseMemorySafety(sysInfo, in.PHP, cfg.AvailableMemoryMB)
+	cfg.Warnings = append(cfg.Warnings, advice.Warnings...)
+	cfg.Recommendations = append(cfg.Recommendations, advice.Recommendations...)
+
+	return cfg
+}
+
+func addApacheRecommendations(cfg *ApacheConfig, sysInfo *system.Info) {
+	if cfg.ServerLimit > 256 {
+		cfg.Recommendations = append(cfg.Recommendations,
+			"ServerLimit above the default of 256 only takes effect after a full restart, not a graceful reload.")
+	}
+
+	if sysInfo.MemTotalMB < 2048 {
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Every mod_php child loads PHP, even for static files. Serve assets from a CDN or a separate server.")
+	}
+
+	cfg.Recommendations = append(cfg.Recommendations,
+		"Consider PHP-FPM with mpm_event: only PHP requests then hold a PHP process.")
+}
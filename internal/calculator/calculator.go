@@ -1,9 +1,12 @@
 package calculator
 
 import (
+	"cmp"
+	"fmt"
 	"math"
+	"strings"
 
-	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
 	"github.com/muuvmuuv/php-tuner/internal/system"
 )
 
@@ -16,15 +19,6 @@ const (
 	PMOnDemand PMType = "ondemand"
 )
 
-// TrafficProfile represents expected traffic patterns
-type TrafficProfile string
-
-const (
-	TrafficLow    TrafficProfile = "low"
-	TrafficMedium TrafficProfile = "medium"
-	TrafficHigh   TrafficProfile = "high"
-)
-
 // Config holds the calculated PHP-FPM configuration
 type Config struct {
 	PM                 PMType
@@ -35,20 +29,34 @@ type Config struct {
 	MaxRequests        int
 	ProcessIdleTimeout string
 
+	// Set when tuning alongside nginx, see CalculateNginx
+	RequestTerminateTimeout string
+	ListenBacklog           int
+
 	// Metadata for display
 	ReservedMemoryMB  int
+	ReservedBreakdown []ReservedItem
 	AvailableMemoryMB int
 	ProcessMemoryMB   float64
 	Warnings          []string
 	Recommendations   []string
+	Trace             Trace
+}
+
+// ReservedItem is one line of the reserved memory breakdown
+type ReservedItem struct {
+	Label    string
+	MemoryMB int
+	Source   string
 }
 
 // Options for calculation
 type Options struct {
-	ReservedMemoryMB int            // Memory reserved for OS/other services
-	ProcessMemoryMB  float64        // Override detected process memory
-	TrafficProfile   TrafficProfile // Expected traffic level
-	PMType           PMType         // Desired PM type (empty = auto)
+	ReservedMemoryMB int     // Memory reserved for OS/other services
+	ProcessMemoryMB  float64 // Override detected process memory
+	TrafficProfile   Profile // Expected traffic pattern
+	PMType           PMType  // Desired PM type (empty = auto)
+	PlateauWorkers   int     // Measured throughput plateau concurrency (0 = unknown)
 }
 
 // DefaultOptions returns sensible defaults
@@ -56,132 +64,233 @@ func DefaultOptions() Options {
 	return Options{
 		ReservedMemoryMB: 0, // Auto-calculate
 		ProcessMemoryMB:  0, // Auto-detect
-		TrafficProfile:   TrafficMedium,
+		TrafficProfile:   DefaultProfile(),
 		PMType:           "", // Auto-select
 	}
 }
 
 // Calculate computes optimal PHP-FPM settings
-func Calculate(sysInfo *system.Info, phpInfo *php.ProcessInfo, opts Options) *Config {
+func Calculate(in Input, opts Options) *Config {
 	cfg := &Config{
 		Warnings:        []string{},
 		Recommendations: []string{},
 	}
+	trace := &cfg.Trace
+	sysInfo := in.System
 
 	// Determine process memory
-	cfg.ProcessMemoryMB = determineProcessMemory(phpInfo, opts)
+	var memSource string
+	cfg.ProcessMemoryMB, memSource = determineProcessMemory(in, opts.ProcessMemoryMB)
+	trace.set("process_memory", "process memory source", memSource,
+		fmt.Sprintf("%.1f MB", cfg.ProcessMemoryMB), "Memory one PHP-FPM worker is expected to use")
 
 	// Determine reserved memory (for OS, DB, web server, etc.)
-	cfg.ReservedMemoryMB = determineReservedMemory(sysInfo, opts)
+	cfg.ReservedMemoryMB, cfg.ReservedBreakdown = determineReservedMemory(in, opts)
+	trace.set("reserved_memory", "sum of reserved items", formatReserved(cfg.ReservedBreakdown),
+		fmt.Sprintf("%d MB", cfg.ReservedMemoryMB), "Memory kept free for the OS and other services")
 
 	// Calculate available memory for PHP-FPM
 	cfg.AvailableMemoryMB = sysInfo.MemTotalMB - cfg.ReservedMemoryMB
+	trace.set("available_memory", "total - reserved",
+		fmt.Sprintf("%d MB - %d MB", sysInfo.MemTotalMB, cfg.ReservedMemoryMB),
+		fmt.Sprintf("%d MB", cfg.AvailableMemoryMB), "Memory budget for all PHP-FPM workers")
 	if cfg.AvailableMemoryMB < 256 {
+		trace.adjust("available_memory", "minimum 256 MB", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB),
+			"256 MB", "Reservation leaves too little memory, falling back to the minimum")
 		cfg.AvailableMemoryMB = 256
 		cfg.Warnings = append(cfg.Warnings, "Very low available memory, using minimum of 256MB")
 	}
 
 	// Determine PM type
 	cfg.PM = determinePMType(opts, sysInfo)
+	if opts.PMType != "" {
+		trace.set("pm", "user override", "--pm", cfg.PM, "Process manager type requested explicitly")
+	} else {
+		trace.set("pm", "traffic profile", opts.TrafficProfile.Name, cfg.PM,
+			"Process manager type chosen for the traffic profile")
+	}
 
 	// Calculate max_children based on available memory and process size
 	if cfg.ProcessMemoryMB > 0 {
 		cfg.MaxChildren = int(math.Floor(float64(cfg.AvailableMemoryMB) / cfg.ProcessMemoryMB))
+		trace.set("pm.max_children", "available / process memory",
+			fmt.Sprintf("%d MB / %.1f MB", cfg.AvailableMemoryMB, cfg.ProcessMemoryMB),
+			cfg.MaxChildren, "As many workers as fit into the memory budget")
 	} else {
 		// Fallback: estimate based on memory_limit or default
 		cfg.MaxChildren = int(math.Floor(float64(cfg.AvailableMemoryMB) / 64)) // Assume 64MB default
 		cfg.Warnings = append(cfg.Warnings, "Could not detect PHP process memory, using 64MB estimate")
+		trace.set("pm.max_children", "available / 64 MB estimate",
+			fmt.Sprintf("%d MB / 64 MB", cfg.AvailableMemoryMB),
+			cfg.MaxChildren, "Process memory unknown, assuming 64 MB per worker")
 	}
 
 	// Apply sanity bounds
 	if cfg.MaxChildren < 5 {
+		trace.adjust("pm.max_children", "minimum 5", cfg.MaxChildren, 5, "Too few workers to serve concurrent requests")
 		cfg.MaxChildren = 5
 		cfg.Warnings = append(cfg.Warnings, "max_children increased to minimum of 5")
 	}
 	if cfg.MaxChildren > 1000 {
+		trace.adjust("pm.max_children", "maximum 1000", cfg.MaxChildren, 1000, "Context switching outweighs more workers")
 		cfg.MaxChildren = 1000
 		cfg.Warnings = append(cfg.Warnings, "max_children capped at 1000")
 	}
 
-	// Calculate other settings based on CPU cores
-	cpuCores := sysInfo.CPUCores
+	// Workers beyond the measured plateau only add memory, not throughput
+	if limit := plateauLimit(opts.PlateauWorkers); limit > 0 && cfg.MaxChildren > limit {
+		trace.adjust("pm.max_children", "<= plateau x 1.25", cfg.MaxChildren, limit,
+			fmt.Sprintf("Throughput plateaued at %d concurrent requests in the benchmark", opts.PlateauWorkers))
+		cfg.MaxChildren = limit
+		cfg.Recommendations = append(cfg.Recommendations, fmt.Sprintf(
+			"max_children limited to %d by the measured throughput plateau, freeing memory for other uses.", limit))
+	}
 
-	cfg.StartServers = cpuCores * 4
-	cfg.MinSpareServers = cpuCores * 2
-	cfg.MaxSpareServers = cpuCores * 4
+	// Calculate other settings based on CPU cores
+	cpuCores := max(sysInfo.CPUCores, 1) // System files may omit the core count
+	cores := fmt.Sprintf("%d CPU cores", cpuCores)
+
+	spares := opts.TrafficProfile.SpareFactor
+	cfg.StartServers = opts.TrafficProfile.scale(cpuCores * 4)
+	cfg.MinSpareServers = opts.TrafficProfile.scale(cpuCores * 2)
+	cfg.MaxSpareServers = opts.TrafficProfile.scale(cpuCores * 4)
+	trace.set("pm.start_servers", fmt.Sprintf("CPU x %g", 4*spares), cores, cfg.StartServers,
+		"Workers forked at startup")
+	trace.set("pm.min_spare_servers", fmt.Sprintf("CPU x %g", 2*spares), cores, cfg.MinSpareServers,
+		"Idle workers kept ready")
+	trace.set("pm.max_spare_servers", fmt.Sprintf("CPU x %g", 4*spares), cores, cfg.MaxSpareServers,
+		"Idle workers before killing extras")
 
 	// Ensure spare servers don't exceed max_children
 	if cfg.StartServers > cfg.MaxChildren {
+		trace.adjust("pm.start_servers", "<= max_children", cfg.StartServers, cfg.MaxChildren,
+			"Cannot start more workers than max_children")
 		cfg.StartServers = cfg.MaxChildren
 	}
 	if cfg.MinSpareServers > cfg.MaxChildren {
+		trace.adjust("pm.min_spare_servers", "<= max_children", cfg.MinSpareServers, cfg.MaxChildren/2,
+			"Half of max_children keeps room for busy workers")
 		cfg.MinSpareServers = cfg.MaxChildren / 2
 	}
 	if cfg.MaxSpareServers > cfg.MaxChildren {
+		trace.adjust("pm.max_spare_servers", "<= max_children", cfg.MaxSpareServers, cfg.MaxChildren,
+			"Cannot keep more idle workers than max_children")
 		cfg.MaxSpareServers = cfg.MaxChildren
 	}
 
 	// Ensure min <= start <= max for spare servers
 	if cfg.MinSpareServers > cfg.StartServers {
+		trace.adjust("pm.min_spare_servers", "<= start_servers", cfg.MinSpareServers, cfg.StartServers,
+			"PHP-FPM requires min_spare_servers <= start_servers")
 		cfg.MinSpareServers = cfg.StartServers
 	}
 	if cfg.MaxSpareServers < cfg.StartServers {
+		trace.adjust("pm.max_spare_servers", ">= start_servers", cfg.MaxSpareServers, cfg.StartServers,
+			"PHP-FPM requires start_servers <= max_spare_servers")
 		cfg.MaxSpareServers = cfg.StartServers
 	}
 
 	// Set idle timeout based on traffic profile
-	switch opts.TrafficProfile {
-	case TrafficLow:
-		cfg.ProcessIdleTimeout = "10s"
-	case TrafficHigh:
-		cfg.ProcessIdleTimeout = "3s"
-	default:
-		cfg.ProcessIdleTimeout = "5s"
-	}
+	cfg.ProcessIdleTimeout = opts.TrafficProfile.IdleTimeout
+	trace.set("pm.process_idle_timeout", "traffic profile", opts.TrafficProfile.Name, cfg.ProcessIdleTimeout,
+		"Longer timeouts keep idle workers around for sparse traffic")
 
 	// max_requests helps prevent memory leaks
-	cfg.MaxRequests = 500
+	cfg.MaxRequests = cmp.Or(opts.TrafficProfile.FPMMaxRequests, opts.TrafficProfile.MaxRequests)
+	trace.set("pm.max_requests", "traffic profile", opts.TrafficProfile.Name, cfg.MaxRequests,
+		"Recycle workers to contain memory leaks")
 
 	// Add recommendations
 	addRecommendations(cfg, sysInfo, opts)
 
+	// Warn about swap/overcommit behaviour once the budget is exceeded
+	advice := adviseMemorySafety(sysInfo, in.PHP, cfg.AvailableMemoryMB)
+	cfg.Warnings = append(cfg.Warnings, advice.Warnings...)
+	cfg.Recommendations = append(cfg.Recommendations, advice.Recommendations...)
+
 	return cfg
 }
 
-func determineProcessMemory(phpInfo *php.ProcessInfo, opts Options) float64 {
-	if opts.ProcessMemoryMB > 0 {
-		return opts.ProcessMemoryMB
+func determineProcessMemory(in Input, overrideMB float64) (float64, string) {
+	if overrideMB > 0 {
+		return overrideMB, "--process-mem"
 	}
 
-	if phpInfo != nil && phpInfo.AvgMemoryMB > 0 {
-		return phpInfo.AvgMemoryMB
+	if in.PHP != nil && in.PHP.AvgMemoryMB > 0 {
+		return in.PHP.AvgMemoryMB, fmt.Sprintf("average RSS of %d processes", in.PHP.ProcessCount)
 	}
 
-	// Try to get memory_limit as upper bound estimate
-	if limit, err := php.GetPHPMemoryLimit(); err == nil && limit > 0 {
+	// Use memory_limit as upper bound estimate
+	if in.MemoryLimitMB > 0 {
 		// Use 50% of memory_limit as estimate (processes rarely use full limit)
-		return float64(limit) / 2
+		return float64(in.MemoryLimitMB) / 2, fmt.Sprintf("50%% of memory_limit %dM", in.MemoryLimitMB)
 	}
 
-	return 0 // Will trigger fallback
+	return 0, "unknown" // Will trigger fallback
+}
+
+// plateauLimit allows 25% headroom above the measured plateau for I/O bound bursts
+func plateauLimit(plateau int) int {
+	if plateau <= 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(plateau) * 1.25))
+}
+
+func formatReserved(items []ReservedItem) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%s %d MB", item.Label, item.MemoryMB))
+	}
+	return strings.Join(parts, " + ")
 }
 
-func determineReservedMemory(sysInfo *system.Info, opts Options) int {
+func determineReservedMemory(in Input, opts Options) (int, []ReservedItem) {
 	if opts.ReservedMemoryMB > 0 {
-		return opts.ReservedMemoryMB
+		return opts.ReservedMemoryMB, []ReservedItem{
+			{Label: "Manual", MemoryMB: opts.ReservedMemoryMB, Source: "--reserved"},
+		}
 	}
 
 	// Auto-calculate: reserve memory for OS and other services
 	// Base: 512MB minimum for OS
 	// Plus: 15% of total memory for buffers/cache/other services
-	reserved := 512 + (sysInfo.MemTotalMB * 15 / 100)
+	reserved := 512 + (in.System.MemTotalMB * 15 / 100)
 
 	// Cap at 4GB for very large memory systems
 	if reserved > 4096 {
 		reserved = 4096
 	}
 
-	return reserved
+	items := []ReservedItem{{Label: "OS/buffers", MemoryMB: reserved, Source: "512 MB + 15%"}}
+
+	return reserveServices(reserved, items, in.Services)
+}
+
+// reserveServices adds detected co-located services on top of the base
+// reservation and returns the total with its itemized breakdown
+func reserveServices(base int, items []ReservedItem, svcs []services.Service) (int, []ReservedItem) {
+	total := base
+
+	for _, svc := range svcs {
+		mem := svc.ReservedMB()
+		if mem == 0 {
+			continue
+		}
+
+		source := "current RSS"
+		switch {
+		case svc.ConfiguredMB > svc.UsedMB():
+			source = svc.ConfigSource
+		case svc.PSSMB > 0:
+			source = "current PSS"
+		}
+
+		items = append(items, ReservedItem{Label: svc.Name, MemoryMB: mem, Source: source})
+		total += mem
+	}
+
+	return total, items
 }
 
 func determinePMType(opts Options, sysInfo *system.Info) PMType {
@@ -189,16 +298,7 @@ func determinePMType(opts Options, sysInfo *system.Info) PMType {
 		return opts.PMType
 	}
 
-	// Auto-select based on traffic profile and resources
-	switch opts.TrafficProfile {
-	case TrafficLow:
-		return PMOnDemand
-	case TrafficHigh:
-		return PMStatic
-	default:
-		// Medium traffic: use dynamic
-		return PMDynamic
-	}
+	return opts.TrafficProfile.PM
 }
 
 func addRecommendations(cfg *Config, sysInfo *system.Info, opts Options) {
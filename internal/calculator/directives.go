@@ -0,0 +1,108 @@
+package calculator
+
+import "fmt"
+
+// Directive is a single configuration setting as written to a config file
+type Directive struct {
+	Name  string
+	Value string
+}
+
+// Directives returns the pool directives that apply to the selected PM type
+func (c *Config) Directives() []Directive {
+	directives := []Directive{
+		{"pm", string(c.PM)},
+		{"pm.max_children", fmt.Sprint(c.MaxChildren)},
+	}
+
+	if c.PM == PMDynamic || c.PM == PMOnDemand {
+		directives = append(directives, Directive{"pm.process_idle_timeout", c.ProcessIdleTimeout})
+	}
+
+	if c.PM == PMDynamic {
+		directives = append(directives,
+			Directive{"pm.start_servers", fmt.Sprint(c.StartServers)},
+			Directive{"pm.min_spare_servers", fmt.Sprint(c.MinSpareServers)},
+			Directive{"pm.max_spare_servers", fmt.Sprint(c.MaxSpareServers)},
+		)
+	}
+
+	directives = append(directives, Directive{"pm.max_requests", fmt.Sprint(c.MaxRequests)})
+
+	if c.RequestTerminateTimeout != "" {
+		directives = append(directives, Directive{"request_terminate_timeout", c.RequestTerminateTimeout})
+	}
+
+	if c.ListenBacklog > 0 {
+		directives = append(directives, Directive{"listen.backlog", fmt.Sprint(c.ListenBacklog)})
+	}
+
+	return directives
+}
+
+// Directives returns the frankenphp global options, worker settings are
+// prefixed with "worker."
+func (c *FrankenPHPConfig) Directives() []Directive {
+	directives := []Directive{{"num_threads", fmt.Sprint(c.NumThreads)}}
+
+	if c.MaxThreads > c.NumThreads {
+		directives = append(directives, Directive{"max_threads", fmt.Sprint(c.MaxThreads)})
+	}
+
+	if c.MaxWaitTime != "" {
+		directives = append(directives, Directive{"max_wait_time", c.MaxWaitTime})
+	}
+
+	if c.WorkerNum > 0 {
+		directives = append(directives, Directive{"worker.num", fmt.Sprint(c.WorkerNum)})
+	}
+
+	return directives
+}
+
+// Directives returns the prefork MPM directives in mpm_prefork.conf order
+func (c *ApacheConfig) Directives() []Directive {
+	return []Directive{
+		{"StartServers", fmt.Sprint(c.StartServers)},
+		{"MinSpareServers", fmt.Sprint(c.MinSpareServers)},
+		{"MaxSpareServers", fmt.Sprint(c.MaxSpareServers)},
+		{"ServerLimit", fmt.Sprint(c.ServerLimit)},
+		{"MaxRequestWorkers", fmt.Sprint(c.MaxRequestWorkers)},
+		{"MaxConnectionsPerChild", fmt.Sprint(c.MaxConnectionsPerChild)},
+	}
+}
+
+// Directives returns the .rr.yaml http.pool settings as dotted paths
+func (c *RoadRunnerConfig) Directives() []Directive {
+	return []Directive{
+		{"http.pool.num_workers", fmt.Sprint(c.NumWorkers)},
+		{"http.pool.max_jobs", fmt.Sprint(c.MaxJobs)},
+		{"http.pool.supervisor.max_worker_memory", fmt.Sprint(c.MaxWorkerMemoryMB)},
+	}
+}
+
+// Directives returns the Swoole server settings passed to $server->set()
+func (c *SwooleConfig) Directives() []Directive {
+	directives := []Directive{
+		{"reactor_num", fmt.Sprint(c.ReactorNum)},
+		{"worker_num", fmt.Sprint(c.WorkerNum)},
+	}
+
+	if c.TaskWorkerNum > 0 {
+		directives = append(directives, Directive{"task_worker_num", fmt.Sprint(c.TaskWorkerNum)})
+	}
+
+	return append(directives, Directive{"max_request", fmt.Sprint(c.MaxRequest)})
+}
+
+// Directives returns the [Service] settings of the drop-in
+func (c *SystemdConfig) Directives() []Directive {
+	return []Directive{
+		{"MemoryHigh", fmt.Sprintf("%dM", c.MemoryHighMB)},
+		{"MemoryMax", fmt.Sprintf("%dM", c.MemoryMaxMB)},
+		{"CPUQuota", fmt.Sprintf("%d%%", c.CPUQuotaPct)},
+		{"LimitNOFILE", fmt.Sprint(c.LimitNOFILE)},
+		{"TasksMax", fmt.Sprint(c.TasksMax)},
+		{"OOMScoreAdjust", fmt.Sprint(c.OOMScoreAdjust)},
+	}
+}
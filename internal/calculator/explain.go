@@ -0,0 +1,38 @@
+package calculator
+
+import "fmt"
+
+// Decision records one rule the calculator applied to a config field
+type Decision struct {
+	Field  string `json:"field"`
+	Rule   string `json:"rule"`
+	Input  string `json:"input,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after"`
+	Reason string `json:"reason"`
+}
+
+// Trace is the ordered list of decisions behind a calculated config
+type Trace []Decision
+
+// set records the initial value of a field
+func (t *Trace) set(field, rule, input string, value any, reason string) {
+	*t = append(*t, Decision{
+		Field:  field,
+		Rule:   rule,
+		Input:  input,
+		After:  fmt.Sprint(value),
+		Reason: reason,
+	})
+}
+
+// adjust records a change to a previously set field
+func (t *Trace) adjust(field, rule string, before, after any, reason string) {
+	*t = append(*t, Decision{
+		Field:  field,
+		Rule:   rule,
+		Before: fmt.Sprint(before),
+		After:  fmt.Sprint(after),
+		Reason: reason,
+	})
+}
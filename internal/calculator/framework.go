@@ -0,0 +1,79 @@
+package calculator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/muuvmuuv/php-tuner/internal/framework"
+)
+
+// frameworkPreset describes how a framework runs in FrankenPHP worker mode
+type frameworkPreset struct {
+	threadMemoryMB float64 // Typical memory of one booted worker thread
+	workerFile     string  // Worker script relative to the project root
+	env            func(maxRequests int) []Directive
+}
+
+var frameworkPresets = map[framework.Framework]frameworkPreset{
+	// A booted Laravel app with its service container is heavier than the
+	// generic estimate; Octane's worker script reads MAX_REQUESTS
+	framework.LaravelOctane: {
+		threadMemoryMB: 60,
+		workerFile:     "public/frankenphp-worker.php",
+		env: func(maxRequests int) []Directive {
+			return []Directive{
+				{"OCTANE_SERVER", "frankenphp"},
+				{"MAX_REQUESTS", fmt.Sprint(maxRequests)},
+			}
+		},
+	},
+	// The Symfony Runtime restarts the worker after FRANKENPHP_LOOP_MAX requests
+	framework.Symfony: {
+		threadMemoryMB: 40,
+		workerFile:     "public/index.php",
+		env: func(maxRequests int) []Directive {
+			return []Directive{
+				{"APP_RUNTIME", `Runtime\FrankenPhpSymfony\Runtime`},
+				{"FRANKENPHP_LOOP_MAX", fmt.Sprint(maxRequests)},
+			}
+		},
+	},
+}
+
+// applyFrameworkPreset sets the worker restart threshold, the default
+// script and the environment of every worker for a detected framework
+func applyFrameworkPreset(cfg *FrankenPHPConfig, opts FrankenPHPOptions) {
+	preset, ok := frameworkPresets[opts.Framework]
+	if !ok || !opts.WorkerMode {
+		return
+	}
+	cfg.Framework = opts.Framework
+
+	// Restarting workers contains leaks from services that keep state
+	cfg.MaxRequests = opts.TrafficProfile.MaxRequests
+	trace := &cfg.Trace
+	trace.set("worker.max_requests", "traffic profile", opts.TrafficProfile.Name, cfg.MaxRequests,
+		"Restart workers regularly to contain memory leaks")
+
+	cfg.Env = preset.env(cfg.MaxRequests)
+	for i := range cfg.Workers {
+		if cfg.Workers[i].File == "" {
+			cfg.Workers[i].File = preset.workerFile
+		}
+		cfg.Workers[i].Env = append(slices.Clone(cfg.Env), cfg.Workers[i].Env...)
+	}
+
+	switch opts.Framework {
+	case framework.LaravelOctane:
+		cfg.Recommendations = append(cfg.Recommendations,
+			fmt.Sprintf("Start Octane with: php artisan octane:frankenphp --workers=%d --max-requests=%d",
+				cfg.WorkerNum, cfg.MaxRequests))
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Avoid resolving request-scoped services into singletons, Octane reuses the container.")
+	case framework.Symfony:
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Require runtime/frankenphp-symfony and set APP_RUNTIME so public/index.php runs as a worker.")
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Implement ResetInterface on services that hold request state.")
+	}
+}
@@ -1,6 +1,13 @@
 package calculator
 
 import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/muuvmuuv/php-tuner/internal/framework"
+	"github.com/muuvmuuv/php-tuner/internal/services"
 	"github.com/muuvmuuv/php-tuner/internal/system"
 )
 
@@ -11,20 +18,50 @@ type FrankenPHPConfig struct {
 	WorkerNum   int
 	MaxWaitTime string
 
+	// Worker blocks; WorkerNum is the sum of their threads
+	Workers []Worker
+
+	// Worker settings of a framework preset, empty without one
+	Framework   framework.Framework
+	MaxRequests int
+	Env         []Directive
+
 	// Metadata for display
 	ReservedMemoryMB  int
+	ReservedBreakdown []ReservedItem
 	AvailableMemoryMB int
 	ThreadMemoryMB    float64
 	Warnings          []string
 	Recommendations   []string
+	Trace             Trace
+}
+
+// WorkerSpec defines one worker script served by FrankenPHP
+type WorkerSpec struct {
+	File           string
+	Share          float64 // Expected share of traffic in percent (0 = split evenly)
+	ThreadMemoryMB float64 // Memory per thread of this worker (0 = default estimate)
+	Env            []Directive
+}
+
+// Worker is a worker block with its share of the thread budget
+type Worker struct {
+	File           string
+	Num            int
+	MaxThreads     int // 0 when the worker does not scale beyond Num
+	ThreadMemoryMB float64
+	Env            []Directive
 }
 
 // FrankenPHPOptions for calculation
 type FrankenPHPOptions struct {
-	ReservedMemoryMB int            // Memory reserved for OS/other services
-	ThreadMemoryMB   float64        // Override detected thread memory
-	TrafficProfile   TrafficProfile // Expected traffic level
-	WorkerMode       bool           // Using worker mode (long-running)
+	ReservedMemoryMB int                 // Memory reserved for OS/other services
+	ThreadMemoryMB   float64             // Override detected thread memory
+	TrafficProfile   Profile             // Expected traffic pattern
+	WorkerMode       bool                // Using worker mode (long-running)
+	PlateauThreads   int                 // Measured throughput plateau concurrency (0 = unknown)
+	Framework        framework.Framework // Worker-mode framework preset (empty = generic)
+	Workers          []WorkerSpec        // Worker scripts (empty = a single worker)
 }
 
 // DefaultFrankenPHPOptions returns sensible defaults
@@ -32,56 +69,85 @@ func DefaultFrankenPHPOptions() FrankenPHPOptions {
 	return FrankenPHPOptions{
 		ReservedMemoryMB: 0, // Auto-calculate
 		ThreadMemoryMB:   0, // Auto-detect
-		TrafficProfile:   TrafficMedium,
+		TrafficProfile:   DefaultProfile(),
 		WorkerMode:       true, // Most FrankenPHP users use worker mode
 	}
 }
 
 // CalculateFrankenPHP computes optimal FrankenPHP settings
-func CalculateFrankenPHP(sysInfo *system.Info, opts FrankenPHPOptions) *FrankenPHPConfig {
+func CalculateFrankenPHP(in Input, opts FrankenPHPOptions) *FrankenPHPConfig {
 	cfg := &FrankenPHPConfig{
 		Warnings:        []string{},
 		Recommendations: []string{},
 	}
+	trace := &cfg.Trace
+	sysInfo := in.System
 
 	// Determine thread memory
 	// FrankenPHP threads are lighter than FPM processes since they share memory
 	// Default estimate: 30MB per thread (vs ~60MB for FPM)
 	cfg.ThreadMemoryMB = opts.ThreadMemoryMB
-	if cfg.ThreadMemoryMB == 0 {
+	preset, hasPreset := frameworkPresets[opts.Framework]
+	switch {
+	case cfg.ThreadMemoryMB > 0:
+		trace.set("thread_memory", "user override", "--thread-mem",
+			fmt.Sprintf("%.1f MB", cfg.ThreadMemoryMB), "Thread memory given explicitly")
+	case hasPreset && opts.WorkerMode:
+		cfg.ThreadMemoryMB = preset.threadMemoryMB
+		trace.set("thread_memory", "framework preset", string(opts.Framework),
+			fmt.Sprintf("%.1f MB", cfg.ThreadMemoryMB), "Typical memory of one booted worker of this framework")
+	default:
 		cfg.ThreadMemoryMB = 30 // Conservative default for FrankenPHP
 		cfg.Warnings = append(cfg.Warnings,
 			"Using estimated 30MB per thread. Use --process-mem to override if known.")
+		trace.set("thread_memory", "default estimate", "", "30.0 MB",
+			"FrankenPHP threads share memory, 30 MB is a conservative estimate")
 	}
 
-	// Determine reserved memory (for OS, Caddy itself, etc.)
-	cfg.ReservedMemoryMB = opts.ReservedMemoryMB
-	if cfg.ReservedMemoryMB == 0 {
-		// FrankenPHP/Caddy needs less reserved memory than nginx+fpm
-		cfg.ReservedMemoryMB = 256 + (sysInfo.MemTotalMB * 10 / 100)
-		if cfg.ReservedMemoryMB > 2048 {
-			cfg.ReservedMemoryMB = 2048
-		}
+	// Workers with their own memory profile shift the average thread
+	shares := workerShares(opts.Workers)
+	if weighted := weightedThreadMemory(opts.Workers, shares, cfg.ThreadMemoryMB); weighted != cfg.ThreadMemoryMB {
+		trace.adjust("thread_memory", "traffic-weighted worker memory",
+			fmt.Sprintf("%.1f MB", cfg.ThreadMemoryMB), fmt.Sprintf("%.1f MB", weighted),
+			"Workers use different amounts of memory per thread")
+		cfg.ThreadMemoryMB = weighted
 	}
 
+	// Determine reserved memory (for OS, Caddy itself, etc.)
+	cfg.ReservedMemoryMB, cfg.ReservedBreakdown = determineServerReserved(sysInfo,
+		opts.ReservedMemoryMB, in.Services, "OS/Caddy")
+	trace.set("reserved_memory", "sum of reserved items", formatReserved(cfg.ReservedBreakdown),
+		fmt.Sprintf("%d MB", cfg.ReservedMemoryMB), "Memory kept free for the OS, Caddy and other services")
+
 	// Calculate available memory for PHP threads
 	cfg.AvailableMemoryMB = sysInfo.MemTotalMB - cfg.ReservedMemoryMB
+	trace.set("available_memory", "total - reserved",
+		fmt.Sprintf("%d MB - %d MB", sysInfo.MemTotalMB, cfg.ReservedMemoryMB),
+		fmt.Sprintf("%d MB", cfg.AvailableMemoryMB), "Memory budget for all PHP threads")
 	if cfg.AvailableMemoryMB < 128 {
+		trace.adjust("available_memory", "minimum 128 MB", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB),
+			"128 MB", "Reservation leaves too little memory, falling back to the minimum")
 		cfg.AvailableMemoryMB = 128
 		cfg.Warnings = append(cfg.Warnings, "Very low available memory, using minimum of 128MB")
 	}
 
-	cpuCores := sysInfo.CPUCores
+	cpuCores := max(sysInfo.CPUCores, 1)
+	cores := fmt.Sprintf("%d CPU cores", cpuCores)
 
 	// Calculate num_threads
-	// FrankenPHP default: 2x CPU cores
+	// FrankenPHP default: 2x CPU cores, scaled by the traffic profile
 	// We calculate based on memory available, but cap reasonably
+	threadFactor := opts.TrafficProfile.ThreadFactor
 	maxByMemory := int(float64(cfg.AvailableMemoryMB) / cfg.ThreadMemoryMB)
-	defaultThreads := cpuCores * 2
+	defaultThreads := max(int(float64(cpuCores)*threadFactor), 1)
 
 	// Use the lower of memory-based or a reasonable CPU-based limit
 	cfg.NumThreads = defaultThreads
+	trace.set("num_threads", fmt.Sprintf("CPU x %g", threadFactor), cores, cfg.NumThreads,
+		"FrankenPHP default thread count")
 	if maxByMemory < cfg.NumThreads {
+		trace.adjust("num_threads", "<= available / thread memory", cfg.NumThreads, maxByMemory,
+			fmt.Sprintf("Only %d threads fit into %d MB", maxByMemory, cfg.AvailableMemoryMB))
 		cfg.NumThreads = maxByMemory
 		cfg.Warnings = append(cfg.Warnings,
 			"Thread count limited by available memory")
@@ -89,45 +155,246 @@ func CalculateFrankenPHP(sysInfo *system.Info, opts FrankenPHPOptions) *FrankenP
 
 	// Sanity bounds
 	if cfg.NumThreads < 2 {
+		trace.adjust("num_threads", "minimum 2", cfg.NumThreads, 2, "At least two threads are needed")
 		cfg.NumThreads = 2
 	}
 	if cfg.NumThreads > 1000 {
+		trace.adjust("num_threads", "maximum 1000", cfg.NumThreads, 1000, "Context switching outweighs more threads")
 		cfg.NumThreads = 1000
 		cfg.Warnings = append(cfg.Warnings, "num_threads capped at 1000")
 	}
 
 	// max_threads for auto-scaling
-	// Allow up to 4x CPU cores or memory limit, whichever is lower
-	cfg.MaxThreads = cpuCores * 4
+	// Allow up to twice the default threads or memory limit, whichever is lower
+	cfg.MaxThreads = defaultThreads * 2
+	trace.set("max_threads", fmt.Sprintf("CPU x %g", 2*threadFactor), cores, cfg.MaxThreads,
+		"Upper bound for automatic thread scaling")
 	if cfg.MaxThreads > maxByMemory {
+		trace.adjust("max_threads", "<= available / thread memory", cfg.MaxThreads, maxByMemory,
+			"Scaled threads must still fit into the memory budget")
 		cfg.MaxThreads = maxByMemory
 	}
 	if cfg.MaxThreads < cfg.NumThreads {
+		trace.adjust("max_threads", ">= num_threads", cfg.MaxThreads, cfg.NumThreads,
+			"max_threads cannot be lower than num_threads")
 		cfg.MaxThreads = cfg.NumThreads
 	}
 
+	// Threads beyond the measured plateau only add memory, not throughput
+	if limit := plateauLimit(opts.PlateauThreads); limit > 0 {
+		reason := fmt.Sprintf("Throughput plateaued at %d concurrent requests in the benchmark", opts.PlateauThreads)
+		if cfg.NumThreads > limit {
+			trace.adjust("num_threads", "<= plateau x 1.25", cfg.NumThreads, max(limit, 2), reason)
+			cfg.NumThreads = max(limit, 2)
+		}
+		if ceiling := max(limit, cfg.NumThreads); cfg.MaxThreads > ceiling {
+			trace.adjust("max_threads", "<= plateau x 1.25", cfg.MaxThreads, ceiling, reason)
+			cfg.MaxThreads = ceiling
+		}
+	}
+
 	// Worker num (for worker mode)
 	// Similar to num_threads but for persistent workers
 	if opts.WorkerMode {
-		cfg.WorkerNum = cfg.NumThreads
+		allocateWorkers(cfg, opts.Workers, shares)
 	}
 
 	// max_wait_time based on traffic profile
-	switch opts.TrafficProfile {
-	case TrafficLow:
-		cfg.MaxWaitTime = "" // Disabled for low traffic
-	case TrafficHigh:
-		cfg.MaxWaitTime = "5s" // Timeout quickly under high load
-	default:
-		cfg.MaxWaitTime = "10s"
+	cfg.MaxWaitTime = opts.TrafficProfile.MaxWaitTime
+	waitTime := cfg.MaxWaitTime
+	if waitTime == "" {
+		waitTime = "disabled"
 	}
+	trace.set("max_wait_time", "traffic profile", opts.TrafficProfile.Name, waitTime,
+		"How long requests may queue for a free thread")
 
 	// Add recommendations
 	addFrankenPHPRecommendations(cfg, sysInfo, opts)
+	applyFrameworkPreset(cfg, opts)
+
+	// Warn about swap/overcommit behaviour once the budget is exceeded
+	advice := adviseMemorySafety(sysInfo, nil, cfg.AvailableMemoryMB)
+	cfg.Warnings = append(cfg.Warnings, advice.Warnings...)
+	cfg.Recommendations = append(cfg.Recommendations, advice.Recommendations...)
 
 	return cfg
 }
 
+// workerShares returns each worker's fraction of the traffic. Workers
+// without a share split what the others leave evenly; shares above 100% in
+// total are scaled down. When every worker has a share, shares below 100%
+// leave the rest to regular, non-worker requests.
+func workerShares(specs []WorkerSpec) []float64 {
+	shares := make([]float64, len(specs))
+	total, unset := 0.0, 0
+	for _, spec := range specs {
+		if spec.Share > 0 {
+			total += spec.Share
+		} else {
+			unset++
+		}
+	}
+
+	rest := 0.0
+	if unset > 0 {
+		rest = max(100-total, 0) / 100 / float64(unset)
+	}
+	for i, spec := range specs {
+		switch {
+		case spec.Share <= 0:
+			shares[i] = rest
+		case total > 100:
+			shares[i] = spec.Share / total
+		default:
+			shares[i] = spec.Share / 100
+		}
+	}
+	return shares
+}
+
+func weightedThreadMemory(specs []WorkerSpec, shares []float64, fallback float64) float64 {
+	if len(specs) == 0 {
+		return fallback
+	}
+
+	weighted, rest := 0.0, 1.0
+	for i, spec := range specs {
+		memory := spec.ThreadMemoryMB
+		if memory <= 0 {
+			memory = fallback
+		}
+		weighted += shares[i] * memory
+		rest -= shares[i]
+	}
+	return weighted + max(rest, 0)*fallback
+}
+
+// allocateWorkers divides num_threads and max_threads among the workers by
+// traffic share. FrankenPHP refuses to start unless num_threads exceeds the
+// sum of worker num, so one thread always stays free for regular requests.
+func allocateWorkers(cfg *FrankenPHPConfig, specs []WorkerSpec, shares []float64) {
+	trace := &cfg.Trace
+
+	if len(specs) == 0 {
+		cfg.WorkerNum = cfg.NumThreads - 1
+		cfg.Workers = []Worker{{Num: cfg.WorkerNum, ThreadMemoryMB: cfg.ThreadMemoryMB}}
+		trace.set("worker.num", "num_threads - 1", "", cfg.WorkerNum,
+			"One persistent worker per thread, one thread stays free for regular requests")
+		return
+	}
+
+	// Every worker needs at least one thread, plus the free one
+	if cfg.NumThreads <= len(specs) {
+		trace.adjust("num_threads", "> number of workers", cfg.NumThreads, len(specs)+1,
+			"Each worker needs at least one thread and one thread stays free")
+		cfg.NumThreads = len(specs) + 1
+		cfg.MaxThreads = max(cfg.MaxThreads, cfg.NumThreads)
+		cfg.Warnings = append(cfg.Warnings, "More workers than the memory budget allows threads for")
+	}
+
+	workerThreads := cfg.NumThreads - 1
+	nums := distribute(workerThreads, shares)
+	assigned := 0
+	for _, num := range nums {
+		assigned += num
+	}
+	for i := range nums {
+		if nums[i] > 0 {
+			continue
+		}
+		// Use an unassigned thread, or take one from the largest worker
+		if assigned < workerThreads {
+			assigned++
+		} else {
+			nums[slices.Index(nums, slices.Max(nums))]--
+		}
+		nums[i] = 1
+	}
+	spare := cfg.MaxThreads - cfg.NumThreads
+	extra := distribute(spare, shares)
+
+	cfg.WorkerNum = 0
+	cfg.Workers = make([]Worker, len(specs))
+	for i, spec := range specs {
+		memory := spec.ThreadMemoryMB
+		if memory <= 0 {
+			memory = cfg.ThreadMemoryMB
+		}
+		worker := Worker{File: spec.File, Num: nums[i], ThreadMemoryMB: memory, Env: spec.Env}
+		if extra[i] > 0 {
+			worker.MaxThreads = worker.Num + extra[i]
+		}
+		cfg.Workers[i] = worker
+		cfg.WorkerNum += worker.Num
+
+		trace.set("worker["+spec.File+"].num", "(num_threads - 1) x share",
+			fmt.Sprintf("%d threads, %.0f%%", workerThreads, shares[i]*100), worker.Num,
+			"Threads kept booted for this worker")
+		if worker.MaxThreads > 0 {
+			trace.set("worker["+spec.File+"].max_threads", "num + (max_threads - num_threads) x share",
+				fmt.Sprintf("%d spare threads", spare), worker.MaxThreads, "Room to scale this worker under load")
+		}
+	}
+
+	if free := cfg.NumThreads - cfg.WorkerNum; free > 1 {
+		cfg.Recommendations = append(cfg.Recommendations, fmt.Sprintf(
+			"%d threads are left for requests that no worker serves.", free))
+	}
+}
+
+// distribute splits total by fractions using the largest remainder method;
+// fractions may sum to less than one, leaving units unassigned
+func distribute(total int, fractions []float64) []int {
+	parts := make([]int, len(fractions))
+	if total <= 0 {
+		return parts
+	}
+
+	type remainder struct {
+		index int
+		value float64
+	}
+	var remainders []remainder
+	used := 0
+	sum := 0.0
+	for i, fraction := range fractions {
+		exact := float64(total) * fraction
+		parts[i] = int(exact)
+		used += parts[i]
+		sum += fraction
+		remainders = append(remainders, remainder{i, exact - float64(parts[i])})
+	}
+
+	// Hand out the units lost to rounding, but never more than the shares claim
+	target := min(total, int(math.Round(float64(total)*sum)))
+	slices.SortStableFunc(remainders, func(a, b remainder) int {
+		return cmp.Compare(b.value, a.value)
+	})
+	for i := 0; used < target && i < len(remainders); i++ {
+		parts[remainders[i].index]++
+		used++
+	}
+
+	return parts
+}
+
+// determineServerReserved reserves memory for runtimes that embed their own
+// server (FrankenPHP, RoadRunner, Swoole), which need less than nginx+fpm
+func determineServerReserved(sysInfo *system.Info, manualMB int, svcs []services.Service,
+	label string) (int, []ReservedItem) {
+	if manualMB > 0 {
+		return manualMB, []ReservedItem{{Label: "Manual", MemoryMB: manualMB, Source: "--reserved"}}
+	}
+
+	base := 256 + (sysInfo.MemTotalMB * 10 / 100)
+	if base > 2048 {
+		base = 2048
+	}
+	items := []ReservedItem{{Label: label, MemoryMB: base, Source: "256 MB + 10%"}}
+
+	return reserveServices(base, items, svcs)
+}
+
 func addFrankenPHPRecommendations(cfg *FrankenPHPConfig, sysInfo *system.Info, opts FrankenPHPOptions) {
 	if opts.WorkerMode {
 		cfg.Recommendations = append(cfg.Recommendations,
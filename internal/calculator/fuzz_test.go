@@ -0,0 +1,150 @@
+package calculator
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/muuvmuuv/php-tuner/internal/framework"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// The fuzz tests assert that every machine and option set the flags accept
+// leads to a config the runtime loads. Run one with e.g.
+//
+//	go test ./internal/calculator -fuzz FuzzCalculateFrankenPHP
+
+var fuzzProfiles = slices.Sorted(maps.Keys(builtinProfiles))
+
+func fuzzProfile(t *testing.T, i uint8) Profile {
+	t.Helper()
+	p, err := LookupProfile(fuzzProfiles[int(i)%len(fuzzProfiles)], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// fuzzInput builds a machine of up to 1024 cores and 4 TB of memory and,
+// when count > 0, running PHP processes of avgMemMB each
+func fuzzInput(cpu uint16, memMB uint32, count uint8, avgMemMB uint16) Input {
+	in := Input{System: system.Simulate(int(cpu%1024)+1, int(memMB%(4<<20))+1)}
+	if count > 0 {
+		in.PHP = &php.ProcessInfo{ProcessCount: int(count), AvgMemoryMB: float64(avgMemMB%4096) + 1}
+		in.PHP.TotalMemMB = in.PHP.AvgMemoryMB * float64(count)
+	}
+	return in
+}
+
+func FuzzCalculate(f *testing.F) {
+	f.Add(uint16(2), uint32(4096), uint32(0), uint16(0), uint8(0), uint16(0), uint8(0), uint8(0), uint16(0))
+	f.Add(uint16(1), uint32(256), uint32(512), uint16(0), uint8(4), uint16(80), uint8(1), uint8(2), uint16(0))
+	f.Add(uint16(64), uint32(262144), uint32(2048), uint16(128), uint8(40), uint16(60), uint8(3), uint8(1), uint16(50))
+
+	pms := []PMType{"", PMStatic, PMDynamic, PMOnDemand}
+	f.Fuzz(func(t *testing.T, cpu uint16, memMB, reservedMB uint32, processMB uint16, count uint8, avgMemMB uint16,
+		profile, pm uint8, plateau uint16) {
+		opts := DefaultOptions()
+		opts.ReservedMemoryMB = int(reservedMB % (4 << 20))
+		opts.ProcessMemoryMB = float64(processMB % 4096)
+		opts.TrafficProfile = fuzzProfile(t, profile)
+		opts.PMType = pms[int(pm)%len(pms)]
+		opts.PlateauWorkers = int(plateau % 10000)
+
+		cfg := Calculate(fuzzInput(cpu, memMB, count, avgMemMB), opts)
+		if err := cfg.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzCalculateFrankenPHP(f *testing.F) {
+	f.Add(uint16(2), uint32(4096), uint32(0), uint16(0), uint8(0), true, uint16(0), uint8(0), uint8(0), uint8(0))
+	f.Add(uint16(1), uint32(256), uint32(512), uint16(0), uint8(1), true, uint16(0), uint8(1), uint8(3), uint8(50))
+	f.Add(uint16(16), uint32(32768), uint32(0), uint16(90), uint8(2), false, uint16(40), uint8(2), uint8(8), uint8(200))
+
+	frameworks := []framework.Framework{framework.None, framework.LaravelOctane, framework.Symfony}
+	f.Fuzz(func(t *testing.T, cpu uint16, memMB, reservedMB uint32, threadMB uint16, profile uint8, workerMode bool,
+		plateau uint16, preset, workers, share uint8) {
+		opts := DefaultFrankenPHPOptions()
+		opts.ReservedMemoryMB = int(reservedMB % (4 << 20))
+		opts.ThreadMemoryMB = float64(threadMB % 4096)
+		opts.TrafficProfile = fuzzProfile(t, profile)
+		opts.WorkerMode = workerMode
+		opts.PlateauThreads = int(plateau % 10000)
+		opts.Framework = frameworks[int(preset)%len(frameworks)]
+		// Every other worker gets a share, the rest split what is left
+		for i := range int(workers % 17) {
+			spec := WorkerSpec{File: "worker" + string(rune('a'+i)) + ".php"}
+			if i%2 == 0 {
+				spec.Share = float64(share)
+			}
+			opts.Workers = append(opts.Workers, spec)
+		}
+
+		cfg := CalculateFrankenPHP(fuzzInput(cpu, memMB, 0, 0), opts)
+		if err := cfg.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzCalculateApache(f *testing.F) {
+	f.Add(uint16(2), uint32(4096), uint32(0), uint16(0), uint8(0), uint16(0), uint8(0))
+	f.Add(uint16(1), uint32(256), uint32(512), uint16(0), uint8(8), uint16(120), uint8(2))
+
+	f.Fuzz(func(t *testing.T, cpu uint16, memMB, reservedMB uint32, processMB uint16, count uint8, avgMemMB uint16,
+		profile uint8) {
+		opts := DefaultApacheOptions()
+		opts.ReservedMemoryMB = int(reservedMB % (4 << 20))
+		opts.ProcessMemoryMB = float64(processMB % 4096)
+		opts.TrafficProfile = fuzzProfile(t, profile)
+
+		cfg := CalculateApache(fuzzInput(cpu, memMB, count, avgMemMB), opts)
+		if err := cfg.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzCalculateRoadRunner(f *testing.F) {
+	f.Add(uint16(2), uint32(4096), uint32(0), uint16(0), uint8(0), uint16(0), uint8(0))
+	f.Add(uint16(1), uint32(1024), uint32(700), uint16(400), uint8(0), uint16(0), uint8(3))
+
+	f.Fuzz(func(t *testing.T, cpu uint16, memMB, reservedMB uint32, workerMB uint16, count uint8, avgMemMB uint16,
+		profile uint8) {
+		opts := DefaultRoadRunnerOptions()
+		opts.ReservedMemoryMB = int(reservedMB % (4 << 20))
+		opts.WorkerMemoryMB = float64(workerMB % 4096)
+		opts.TrafficProfile = fuzzProfile(t, profile)
+
+		cfg := CalculateRoadRunner(fuzzInput(cpu, memMB, count, avgMemMB), opts)
+		if err := cfg.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if float64(cfg.MaxWorkerMemoryMB) < cfg.WorkerMemoryMB {
+			t.Fatalf("max_worker_memory %d MB is below the worker memory %.1f MB", cfg.MaxWorkerMemoryMB, cfg.WorkerMemoryMB)
+		}
+	})
+}
+
+func FuzzCalculateSwoole(f *testing.F) {
+	f.Add(uint16(2), uint32(4096), uint32(0), uint16(0), uint8(0), uint16(0), uint8(0), false, false)
+	f.Add(uint16(1), uint32(256), uint32(512), uint16(200), uint8(0), uint16(0), uint8(4), true, true)
+
+	f.Fuzz(func(t *testing.T, cpu uint16, memMB, reservedMB uint32, workerMB uint16, count uint8, avgMemMB uint16,
+		profile uint8, coroutine, taskWorkers bool) {
+		opts := DefaultSwooleOptions()
+		opts.ReservedMemoryMB = int(reservedMB % (4 << 20))
+		opts.WorkerMemoryMB = float64(workerMB % 4096)
+		opts.TrafficProfile = fuzzProfile(t, profile)
+		opts.Coroutine = coroutine
+		opts.TaskWorkers = taskWorkers
+
+		cfg := CalculateSwoole(fuzzInput(cpu, memMB, count, avgMemMB), opts)
+		if err := cfg.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
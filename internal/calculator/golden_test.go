@@ -0,0 +1,90 @@
+package calculator
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenShapes are the simulated machines the golden files cover
+var goldenShapes = []struct{ cpu, memMB int }{
+	{1, 1024},
+	{2, 4096},
+	{4, 8192},
+	{8, 16384},
+	{32, 131072},
+}
+
+var goldenProfiles = []string{"low", "medium", "high"}
+
+// golden renders one config per profile and machine and compares the result
+// with testdata/<name>.golden; go test -update rewrites it
+func golden(t *testing.T, name string, render func(Input, Profile) ([]Directive, []string)) {
+	t.Helper()
+
+	var b strings.Builder
+	for _, profileName := range goldenProfiles {
+		profile, err := LookupProfile(profileName, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, shape := range goldenShapes {
+			directives, warnings := render(Input{System: system.Simulate(shape.cpu, shape.memMB)}, profile)
+			fmt.Fprintf(&b, "== %s, %d CPU, %d MB\n", profileName, shape.cpu, shape.memMB)
+			for _, d := range directives {
+				fmt.Fprintf(&b, "%s = %s\n", d.Name, d.Value)
+			}
+			for _, w := range warnings {
+				fmt.Fprintf(&b, "! %s\n", w)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != string(want) {
+		t.Errorf("%s differs, run go test -update and review the diff:\n%s", path, got)
+	}
+}
+
+func TestCalculateGolden(t *testing.T) {
+	golden(t, "fpm", func(in Input, profile Profile) ([]Directive, []string) {
+		opts := DefaultOptions()
+		opts.TrafficProfile = profile
+		cfg := Calculate(in, opts)
+		if err := cfg.Validate(); err != nil {
+			t.Error(err)
+		}
+		return cfg.Directives(), cfg.Warnings
+	})
+}
+
+func TestCalculateFrankenPHPGolden(t *testing.T) {
+	golden(t, "frankenphp", func(in Input, profile Profile) ([]Directive, []string) {
+		opts := DefaultFrankenPHPOptions()
+		opts.TrafficProfile = profile
+		cfg := CalculateFrankenPHP(in, opts)
+		if err := cfg.Validate(); err != nil {
+			t.Error(err)
+		}
+		return cfg.Directives(), cfg.Warnings
+	})
+}
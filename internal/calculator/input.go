@@ -0,0 +1,17 @@
+package calculator
+
+import (
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Input holds everything detected about the machine being tuned. It is
+// gathered up front so the Calculate functions never touch the host and
+// always return the same config for the same Input and options.
+type Input struct {
+	System        *system.Info
+	PHP           *php.ProcessInfo   // Running PHP processes (nil = none detected)
+	Services      []services.Service // Co-located services to reserve memory for
+	MemoryLimitMB int                // PHP memory_limit (0 = unknown, -1 = unlimited)
+}
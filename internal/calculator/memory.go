@@ -0,0 +1,127 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Swap, overcommit and PSI thresholds used for memory safety advice
+const (
+	highSwappiness   = 30
+	highPressureAvg  = 10.0
+	recommendedOOM   = 500
+	memoryMaxPercent = 110
+)
+
+// memoryAdvice collects warnings and recommendations about how the system
+// behaves once PHP exceeds its memory budget (swap, overcommit, OOM killer)
+type memoryAdvice struct {
+	Warnings        []string
+	Recommendations []string
+}
+
+func adviseMemorySafety(sysInfo *system.Info, phpInfo *php.ProcessInfo, budgetMB int) memoryAdvice {
+	var advice memoryAdvice
+
+	switch {
+	case sysInfo.SwapTotalMB == 0:
+		advice.Warnings = append(advice.Warnings,
+			"No swap configured: exceeding the memory budget invokes the OOM killer immediately")
+	case sysInfo.Zram || sysInfo.Zswap:
+		// Compressed swap absorbs short spikes without disk I/O
+	case sysInfo.Swappiness >= highSwappiness:
+		advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+			"Disk swap with vm.swappiness=%d: workers over budget will be swapped out and latency degrades",
+			sysInfo.Swappiness))
+		advice.Recommendations = append(advice.Recommendations,
+			"Lower vm.swappiness to 10 so PHP workers stay in RAM and swap is a last resort.")
+	}
+
+	if sysInfo.SwapTotalMB > 0 && sysInfo.SwapFreeMB < sysInfo.SwapTotalMB/2 {
+		advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+			"Swap is %d%% used: the system is already swapping",
+			100-sysInfo.SwapFreeMB*100/sysInfo.SwapTotalMB))
+	}
+
+	switch sysInfo.Overcommit {
+	case 1:
+		advice.Warnings = append(advice.Warnings,
+			"vm.overcommit_memory=1 never refuses allocations: overload surfaces as OOM kills, not PHP errors")
+	case 2:
+		headroom := sysInfo.CommitLimitMB - sysInfo.CommittedMB
+		if sysInfo.CommitLimitMB > 0 && budgetMB > headroom {
+			advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+				"Strict overcommit (vm.overcommit_memory=2): only %d MB commit headroom for a %d MB budget",
+				headroom, budgetMB))
+		}
+	}
+
+	if psi := sysInfo.MemPressure; psi != nil && psi.SomeAvg60 >= highPressureAvg {
+		advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+			"Memory pressure is high (PSI some avg60=%.1f%%): tasks are already stalling on memory",
+			psi.SomeAvg60))
+	}
+
+	if cg := sysInfo.Cgroup; cg != nil && cg.MemoryMaxMB > 0 && cg.MemoryMaxMB < sysInfo.MemTotalMB {
+		advice.Warnings = append(advice.Warnings, fmt.Sprintf(
+			"cgroup memory limit is %d MB, below the %d MB of host memory the budget is based on",
+			cg.MemoryMaxMB, sysInfo.MemTotalMB))
+	}
+
+	if minOOMScoreAdj(phpInfo) < 0 {
+		advice.Warnings = append(advice.Warnings,
+			"PHP processes have a negative oom_score_adj: the kernel will kill other services first")
+	}
+
+	advice.Recommendations = append(advice.Recommendations, fmt.Sprintf(
+		"Set OOMScoreAdjust=%d on the PHP service so PHP workers are killed before databases.",
+		recommendedOOM))
+	advice.Recommendations = append(advice.Recommendations, fmt.Sprintf(
+		"Set MemoryHigh=%dM and MemoryMax=%dM on the PHP service to enforce the memory budget.",
+		budgetMB, budgetMB*memoryMaxPercent/100))
+
+	return advice
+}
+
+func minOOMScoreAdj(phpInfo *php.ProcessInfo) int {
+	lowest := 0
+	if phpInfo == nil {
+		return lowest
+	}
+
+	for _, proc := range phpInfo.Processes {
+		if proc.OOMScoreAdj < lowest {
+			lowest = proc.OOMScoreAdj
+		}
+	}
+
+	return lowest
+}
+
+// OOMRiskScore estimates from 0 (safe) to 1 (OOM kill imminent) how likely
+// the kernel is to kill PHP, weighing budget usage, host memory usage,
+// missing swap and current memory pressure
+func OOMRiskScore(sysInfo *system.Info, usedMB float64, budgetMB int) float64 {
+	if budgetMB <= 0 {
+		return 1
+	}
+
+	score := 0.6 * math.Min(usedMB/float64(budgetMB), 1)
+
+	if sysInfo.MemTotalMB > 0 {
+		score += 0.25 * float64(sysInfo.MemUsedMB) / float64(sysInfo.MemTotalMB)
+	}
+
+	if sysInfo.SwapTotalMB == 0 && !sysInfo.Zswap {
+		score += 0.15
+	}
+
+	if psi := sysInfo.MemPressure; psi != nil {
+		score += psi.FullAvg60 / 100
+	}
+
+	return math.Max(0, math.Min(score, 1))
+}
@@ -0,0 +1,206 @@
+package calculator
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/nginx"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// NginxConfig holds the nginx settings that match a PHP-FPM pool
+type NginxConfig struct {
+	WorkerProcesses    string
+	WorkerConnections  int
+	WorkerRlimitNofile int
+	FastCGIBufferSize  string
+	FastCGIBuffers     string
+	FastCGIReadTimeout string
+	Upstream           string // Upstream block of the FPM pool, empty for a direct fastcgi_pass
+	UpstreamKeepalive  int
+
+	// Pool settings nginx relies on, for the caller to set on the FPM config
+	RequestTerminateTimeout string
+	ListenBacklog           int
+
+	Warnings        []string
+	Recommendations []string
+	Trace           Trace
+}
+
+// NginxOptions describe the deployed FPM pool nginx talks to
+type NginxOptions struct {
+	PoolListen              string // Pool listen address, empty when unknown
+	RequestTerminateTimeout string // Deployed request_terminate_timeout, empty when unset
+}
+
+// Default request_terminate_timeout when the pool has none; unlimited lets
+// a hung script hold a worker forever
+const defaultRequestTimeout = 60
+
+// CalculateNginx aligns nginx with the recommended PHP-FPM pool and returns
+// the request_terminate_timeout and listen.backlog the pool needs to match;
+// fpm is not modified. current is the deployed nginx config and may be nil.
+func CalculateNginx(sysInfo *system.Info, fpm *Config, current *nginx.Config, opts NginxOptions) *NginxConfig {
+	cfg := &NginxConfig{
+		Warnings:        []string{},
+		Recommendations: []string{},
+	}
+	trace := &cfg.Trace
+	if current == nil {
+		current = &nginx.Config{Upstreams: map[string]nginx.Upstream{}, FastCGI: map[string]string{}}
+	}
+
+	cpuCores := max(sysInfo.CPUCores, 1)
+	cores := fmt.Sprintf("%d CPU cores", cpuCores)
+
+	// One event loop per core handles thousands of connections
+	cfg.WorkerProcesses = "auto"
+	trace.set("worker_processes", "one per core", cores, cfg.WorkerProcesses,
+		"nginx workers are event driven and never need more than one per core")
+
+	// Every PHP request holds a client and an upstream connection, and idle
+	// keep-alive clients outnumber busy ones several times
+	needed := fpm.MaxChildren * 2 * 4 / cpuCores
+	cfg.WorkerConnections = max(1024, roundUp(needed, 512))
+	trace.set("worker_connections", "max(1024, max_children x 2 x 4 / CPU)",
+		fmt.Sprintf("%d children, %s", fpm.MaxChildren, cores), cfg.WorkerConnections,
+		"Room for client and upstream connections plus idle keep-alive clients")
+
+	cfg.WorkerRlimitNofile = cfg.WorkerConnections * 2
+	trace.set("worker_rlimit_nofile", "worker_connections x 2", "", cfg.WorkerRlimitNofile,
+		"Each connection may also hold a file or temp file descriptor")
+
+	// Headers go to fastcgi_buffer_size, bodies are buffered in memory up to
+	// fastcgi_buffers before spilling to temp files
+	cfg.FastCGIBufferSize = "32k"
+	cfg.FastCGIBuffers = "16 16k"
+	if sysInfo.MemTotalMB < 2048 {
+		cfg.FastCGIBuffers = "8 16k"
+	}
+	trace.set("fastcgi_buffer_size", "fixed", "", cfg.FastCGIBufferSize,
+		"Fits framework responses with many cookies and headers")
+	trace.set("fastcgi_buffers", "system memory", fmt.Sprintf("%d MB", sysInfo.MemTotalMB), cfg.FastCGIBuffers,
+		"Typical HTML and JSON responses stay in memory")
+
+	// FPM must give up on a request before nginx does, otherwise the script
+	// keeps running after the client already got a 504
+	requestTimeout := defaultRequestTimeout
+	source := "default"
+	if opts.RequestTerminateTimeout != "" {
+		if d, err := ParseNginxDuration(opts.RequestTerminateTimeout); err == nil && d >= time.Second {
+			requestTimeout, source = int(d/time.Second), "request_terminate_timeout = "+opts.RequestTerminateTimeout
+		}
+	}
+	if source == "default" {
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf(
+			"request_terminate_timeout is not set: hung scripts hold workers forever, using %ds", requestTimeout))
+	}
+	cfg.RequestTerminateTimeout = fmt.Sprintf("%ds", requestTimeout)
+	trace.set("request_terminate_timeout", "deployed or 60s", source, cfg.RequestTerminateTimeout,
+		"Upper bound for a single PHP request")
+
+	cfg.FastCGIReadTimeout = fmt.Sprintf("%ds", requestTimeout+5)
+	trace.set("fastcgi_read_timeout", "request_terminate_timeout + 5s", cfg.RequestTerminateTimeout,
+		cfg.FastCGIReadTimeout, "PHP-FPM terminates a request before nginx gives up on it")
+
+	if deployed, ok := current.FastCGI["fastcgi_read_timeout"]; ok {
+		if d, err := ParseNginxDuration(deployed); err == nil && int(d/time.Second) < requestTimeout {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf(
+				"fastcgi_read_timeout %s is shorter than request_terminate_timeout %s: nginx returns 504 while PHP keeps running",
+				deployed, cfg.RequestTerminateTimeout))
+		}
+	}
+
+	// Requests wait in the listen backlog while all children are busy
+	cfg.ListenBacklog = max(511, fpm.MaxChildren*4)
+	trace.set("listen.backlog", "max(511, max_children x 4)", fmt.Sprintf("%d children", fpm.MaxChildren),
+		cfg.ListenBacklog, "Queue bursts instead of refusing connections while all workers are busy")
+	if sysInfo.SoMaxConn > 0 && cfg.ListenBacklog > sysInfo.SoMaxConn {
+		cfg.Recommendations = append(cfg.Recommendations, fmt.Sprintf(
+			"Raise net.core.somaxconn to at least %d, the kernel caps listen.backlog at %d.",
+			cfg.ListenBacklog, sysInfo.SoMaxConn))
+	}
+
+	checkFastCGIPass(cfg, current, fpm, opts)
+
+	return cfg
+}
+
+// checkFastCGIPass verifies nginx talks to the pool and sizes upstream
+// keep-alive, which pins one FPM worker per idle connection
+func checkFastCGIPass(cfg *NginxConfig, current *nginx.Config, fpm *Config, opts NginxOptions) {
+	trace := &cfg.Trace
+
+	if current.Path != "" && len(current.FastCGIPass) == 0 {
+		cfg.Warnings = append(cfg.Warnings, "No fastcgi_pass found in the nginx configuration")
+		return
+	}
+
+	listen := normalizeAddress(opts.PoolListen)
+	for _, target := range current.FastCGIPass {
+		upstream, isUpstream := current.Upstreams[target]
+		servers := []string{target}
+		if isUpstream {
+			servers = upstream.Servers
+			cfg.Upstream = target
+		}
+
+		if listen != "" && !matchesAny(listen, servers) {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf(
+				"fastcgi_pass %s does not point to the pool's listen address %s", target, opts.PoolListen))
+		}
+
+		if !isUpstream || upstream.Keepalive == 0 {
+			continue
+		}
+
+		cfg.UpstreamKeepalive = upstream.Keepalive
+		limit := max(fpm.MaxChildren/2, 1)
+		if upstream.Keepalive > limit {
+			trace.adjust("upstream.keepalive", "<= max_children / 2", upstream.Keepalive, limit,
+				"Every idle keep-alive connection occupies a PHP-FPM worker")
+			cfg.UpstreamKeepalive = limit
+		}
+		if current.FastCGI["fastcgi_keep_conn"] != "on" {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf(
+				"upstream %s sets keepalive without fastcgi_keep_conn on, connections are closed anyway", target))
+		}
+	}
+}
+
+func normalizeAddress(addr string) string {
+	addr = strings.TrimPrefix(strings.TrimSpace(addr), "unix:")
+	if strings.HasPrefix(addr, "/") {
+		return addr
+	}
+	// FPM accepts a bare port for all interfaces
+	if !strings.Contains(addr, ":") && addr != "" {
+		return "127.0.0.1:" + addr
+	}
+	return strings.Replace(addr, "localhost:", "127.0.0.1:", 1)
+}
+
+func matchesAny(listen string, servers []string) bool {
+	for _, server := range servers {
+		target := normalizeAddress(server)
+		if target == listen {
+			return true
+		}
+		// A pool on 0.0.0.0 or [::] accepts any local address
+		host, port, err := net.SplitHostPort(listen)
+		if err != nil || (host != "0.0.0.0" && host != "::") {
+			continue
+		}
+		if _, targetPort, err := net.SplitHostPort(target); err == nil && targetPort == port {
+			return true
+		}
+	}
+	return false
+}
+
+func roundUp(n, multiple int) int {
+	return (n + multiple - 1) / multiple * multiple
+}
@@ -0,0 +1,26 @@
+package calculator
+
+import "testing"
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		listen  string
+		servers []string
+		want    bool
+	}{
+		{"/run/php/php-fpm.sock", []string{"unix:/run/php/php-fpm.sock"}, true},
+		{"127.0.0.1:9000", []string{"localhost:9000"}, true},
+		{"127.0.0.1:9000", []string{"9000"}, true},
+		{"0.0.0.0:9000", []string{"10.0.0.5:9000"}, true},
+		{"[::]:9000", []string{"127.0.0.1:9000"}, true},
+		{"[::]:9000", []string{"[::1]:9000"}, true},
+		{"[::]:9000", []string{"127.0.0.1:9001"}, false},
+		{"[::1]:9000", []string{"127.0.0.1:9000"}, false},
+		{"127.0.0.1:9000", []string{"127.0.0.1:9001", "unix:/run/php/php-fpm.sock"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchesAny(normalizeAddress(tt.listen), tt.servers); got != tt.want {
+			t.Errorf("matchesAny(%q, %q) = %v, want %v", tt.listen, tt.servers, got, tt.want)
+		}
+	}
+}
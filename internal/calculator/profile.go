@@ -0,0 +1,168 @@
+package calculator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Profile describes a traffic pattern as the settings it leads to. All
+// factors are relative to the medium profile.
+type Profile struct {
+	Name         string  `json:"-"`
+	PM           PMType  `json:"pm"`            // PHP-FPM process manager
+	SpareFactor  float64 `json:"spare_factor"`  // Spare servers and worker pools (1 = medium)
+	ThreadFactor float64 `json:"thread_factor"` // FrankenPHP threads per CPU core
+	IdleTimeout  string  `json:"idle_timeout"`  // pm.process_idle_timeout
+	MaxWaitTime  string  `json:"max_wait_time"` // FrankenPHP max_wait_time, empty disables it
+	MaxRequests  int     `json:"max_requests"`  // Requests before a worker is recycled
+
+	// pm.max_requests, 0 = MaxRequests. Forked PHP-FPM children are cheaper
+	// to replace than booted workers, so they may be recycled sooner.
+	FPMMaxRequests int `json:"fpm_max_requests"`
+}
+
+var builtinProfiles = map[string]Profile{
+	// Few requests: spawn workers on demand and let requests queue
+	"low": {PM: PMOnDemand, SpareFactor: 0.5, ThreadFactor: 2, IdleTimeout: "10s", MaxRequests: 500},
+	"medium": {PM: PMDynamic, SpareFactor: 1, ThreadFactor: 2, IdleTimeout: "5s", MaxWaitTime: "10s",
+		MaxRequests: 500},
+	// Sustained load: keep every worker running and fail fast when saturated
+	"high": {PM: PMStatic, SpareFactor: 2, ThreadFactor: 2, IdleTimeout: "3s", MaxWaitTime: "5s",
+		MaxRequests: 1000, FPMMaxRequests: 500},
+	// Short spikes: many spares, kept around long enough for the next spike
+	"bursty": {PM: PMDynamic, SpareFactor: 2, ThreadFactor: 2, IdleTimeout: "30s", MaxWaitTime: "10s",
+		MaxRequests: 500},
+	// Short, uniform requests behind a load balancer that retries elsewhere
+	"api": {PM: PMStatic, SpareFactor: 1, ThreadFactor: 2, IdleTimeout: "5s", MaxWaitTime: "2s",
+		MaxRequests: 1000, FPMMaxRequests: 500},
+	// Long-running requests: few workers, queue instead of rejecting,
+	// recycle often since large jobs fragment memory
+	"batch": {PM: PMDynamic, SpareFactor: 0.5, ThreadFactor: 1, IdleTimeout: "30s", MaxRequests: 100},
+	// Scheduled jobs that arrive at once and leave the pool idle otherwise
+	"cron": {PM: PMOnDemand, SpareFactor: 0.5, ThreadFactor: 1, IdleTimeout: "10s", MaxRequests: 50},
+}
+
+// DefaultProfile returns the medium profile
+func DefaultProfile() Profile {
+	p, _ := LookupProfile("medium", nil)
+	return p
+}
+
+// LookupProfile returns a custom profile or a built-in one; unknown names
+// are an error rather than a silent fallback
+func LookupProfile(name string, custom map[string]Profile) (Profile, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if p, ok := custom[name]; ok {
+		return p, nil
+	}
+	if p, ok := builtinProfiles[name]; ok {
+		p.Name = name
+		return p, nil
+	}
+
+	names := slices.Sorted(maps.Keys(builtinProfiles))
+	for _, n := range slices.Sorted(maps.Keys(custom)) {
+		if !slices.Contains(names, n) {
+			names = append(names, n)
+		}
+	}
+	return Profile{}, fmt.Errorf("unknown traffic profile %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// LoadProfiles reads custom profiles from a JSON object of name to profile.
+// Each profile starts from its "base" profile (default medium) and
+// overrides the fields it sets:
+//
+//	{"checkout": {"base": "high", "max_wait_time": "2s"}}
+func LoadProfiles(path string) (map[string]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	profiles := map[string]Profile{}
+	for name, def := range raw {
+		name = strings.ToLower(name)
+
+		var ref struct {
+			Base           string `json:"base"`
+			MaxRequests    *int   `json:"max_requests"`
+			FPMMaxRequests *int   `json:"fpm_max_requests"`
+		}
+		if err := json.Unmarshal(def, &ref); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+		if ref.Base == "" {
+			ref.Base = "medium"
+		}
+
+		// Only built-ins can be a base, so definition order does not matter
+		base, err := LookupProfile(ref.Base, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+
+		// Reject misspelled fields instead of silently keeping the base value
+		entry := struct {
+			Profile
+			Base string `json:"base"`
+		}{Profile: base}
+		dec := json.NewDecoder(bytes.NewReader(def))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("%s: profile %q: %w", path, name, err)
+		}
+		profile := entry.Profile
+		profile.Name = name
+
+		// A custom max_requests also applies to PHP-FPM unless it is set apart
+		if ref.MaxRequests != nil && ref.FPMMaxRequests == nil {
+			profile.FPMMaxRequests = 0
+		}
+
+		if err := profile.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		profiles[name] = profile
+	}
+
+	return profiles, nil
+}
+
+// Validate checks that a profile yields usable settings
+func (p Profile) Validate() error {
+	var v violations
+
+	v.check(p.PM == PMStatic || p.PM == PMDynamic || p.PM == PMOnDemand,
+		"pm(%q) must be static, dynamic or ondemand", p.PM)
+	v.check(p.SpareFactor > 0, "spare_factor(%g) must be positive", p.SpareFactor)
+	v.check(p.ThreadFactor > 0, "thread_factor(%g) must be positive", p.ThreadFactor)
+	v.checkDuration("idle_timeout", p.IdleTimeout)
+	if p.MaxWaitTime != "" {
+		v.checkDuration("max_wait_time", p.MaxWaitTime)
+	}
+	v.check(p.MaxRequests >= 0, "max_requests(%d) must not be negative", p.MaxRequests)
+	v.check(p.FPMMaxRequests >= 0, "fpm_max_requests(%d) must not be negative", p.FPMMaxRequests)
+
+	if len(v) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid traffic profile %q: %w", p.Name, errors.Join(v...))
+}
+
+// scale multiplies a per-core count by the profile's spare factor, keeping
+// at least one
+func (p Profile) scale(n int) int {
+	return max(int(float64(n)*p.SpareFactor), 1)
+}
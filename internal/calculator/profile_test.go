@@ -0,0 +1,50 @@
+package calculator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+func TestFPMMaxRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	err := os.WriteFile(path, []byte(`{
+		"checkout": {"base": "high", "max_wait_time": "2s"},
+		"long": {"base": "high", "max_requests": 2000},
+		"split": {"base": "high", "max_requests": 2000, "fpm_max_requests": 300}
+	}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile string
+		want    int
+	}{
+		{"medium", 500},
+		{"high", 500},
+		{"api", 500},
+		{"batch", 100},
+		{"checkout", 500},
+		{"long", 2000},
+		{"split", 300},
+	}
+	for _, tt := range tests {
+		profile, err := LookupProfile(tt.profile, custom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := DefaultOptions()
+		opts.TrafficProfile = profile
+		cfg := Calculate(Input{System: system.Simulate(4, 8192)}, opts)
+		if cfg.MaxRequests != tt.want {
+			t.Errorf("%s: pm.max_requests = %d, want %d", tt.profile, cfg.MaxRequests, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// RoadRunnerConfig holds the calculated RoadRunner http.pool configuration
+type RoadRunnerConfig struct {
+	NumWorkers        int
+	MaxJobs           int
+	MaxWorkerMemoryMB int
+
+	// Metadata for display
+	ReservedMemoryMB  int
+	ReservedBreakdown []ReservedItem
+	AvailableMemoryMB int
+	WorkerMemoryMB    float64
+	Warnings          []string
+	Recommendations   []string
+	Trace             Trace
+}
+
+// RoadRunnerOptions for calculation
+type RoadRunnerOptions struct {
+	ReservedMemoryMB int     // Memory reserved for OS/other services
+	WorkerMemoryMB   float64 // Override detected worker memory
+	TrafficProfile   Profile // Expected traffic pattern
+}
+
+// DefaultRoadRunnerOptions returns sensible defaults
+func DefaultRoadRunnerOptions() RoadRunnerOptions {
+	return RoadRunnerOptions{
+		ReservedMemoryMB: 0, // Auto-calculate
+		WorkerMemoryMB:   0, // Auto-detect
+		TrafficProfile:   DefaultProfile(),
+	}
+}
+
+// CalculateRoadRunner computes optimal RoadRunner pool settings
+func CalculateRoadRunner(in Input, opts RoadRunnerOptions) *RoadRunnerConfig {
+	cfg := &RoadRunnerConfig{
+		Warnings:        []string{},
+		Recommendations: []string{},
+	}
+	trace := &cfg.Trace
+	sysInfo, phpInfo := in.System, in.PHP
+
+	// Determine worker memory
+	// RoadRunner workers are long-running PHP CLI processes, like FPM workers
+	// that keep the application booted
+	switch {
+	case opts.WorkerMemoryMB > 0:
+		cfg.WorkerMemoryMB = opts.WorkerMemoryMB
+		trace.set("worker_memory", "user override", "--worker-mem",
+			fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB), "Worker memory given explicitly")
+	case phpInfo != nil && phpInfo.AvgMemoryMB > 0:
+		cfg.WorkerMemoryMB = phpInfo.AvgMemoryMB
+		trace.set("worker_memory", "measured", fmt.Sprintf("average RSS of %d workers", phpInfo.ProcessCount),
+			fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB), "Memory one booted worker uses")
+	default:
+		cfg.WorkerMemoryMB = 50
+		cfg.Warnings = append(cfg.Warnings,
+			"No RoadRunner workers detected, using 50MB estimate. Use --worker-mem to override.")
+		trace.set("worker_memory", "default estimate", "", "50.0 MB",
+			"Booted framework workers typically use 30-80 MB")
+	}
+
+	cfg.ReservedMemoryMB, cfg.ReservedBreakdown = determineServerReserved(sysInfo,
+		opts.ReservedMemoryMB, in.Services, "OS/RoadRunner")
+	trace.set("reserved_memory", "sum of reserved items", formatReserved(cfg.ReservedBreakdown),
+		fmt.Sprintf("%d MB", cfg.ReservedMemoryMB), "Memory kept free for the OS, rr and other services")
+
+	cfg.AvailableMemoryMB = sysInfo.MemTotalMB - cfg.ReservedMemoryMB
+	trace.set("available_memory", "total - reserved",
+		fmt.Sprintf("%d MB - %d MB", sysInfo.MemTotalMB, cfg.ReservedMemoryMB),
+		fmt.Sprintf("%d MB", cfg.AvailableMemoryMB), "Memory budget for all PHP workers")
+	if cfg.AvailableMemoryMB < 128 {
+		trace.adjust("available_memory", "minimum 128 MB", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB),
+			"128 MB", "Reservation leaves too little memory, falling back to the minimum")
+		cfg.AvailableMemoryMB = 128
+		cfg.Warnings = append(cfg.Warnings, "Very low available memory, using minimum of 128MB")
+	}
+
+	cpuCores := max(sysInfo.CPUCores, 1)
+	cores := fmt.Sprintf("%d CPU cores", cpuCores)
+
+	// RoadRunner defaults to one worker per core, which only suits CPU bound
+	// apps; most apps wait on I/O and benefit from more workers
+	factor := opts.TrafficProfile.scale(2)
+	cfg.NumWorkers = cpuCores * factor
+	trace.set("http.pool.num_workers", "CPU x traffic factor",
+		fmt.Sprintf("%s, %s", cores, opts.TrafficProfile.Name), cfg.NumWorkers, "Workers kept booted to serve requests")
+
+	maxByMemory := int(math.Floor(float64(cfg.AvailableMemoryMB) / cfg.WorkerMemoryMB))
+	if maxByMemory < cfg.NumWorkers {
+		trace.adjust("http.pool.num_workers", "<= available / worker memory", cfg.NumWorkers, maxByMemory,
+			fmt.Sprintf("Only %d workers fit into %d MB", maxByMemory, cfg.AvailableMemoryMB))
+		cfg.NumWorkers = maxByMemory
+		cfg.Warnings = append(cfg.Warnings, "Worker count limited by available memory")
+	}
+	if cfg.NumWorkers < 2 {
+		trace.adjust("http.pool.num_workers", "minimum 2", cfg.NumWorkers, 2, "At least two workers are needed")
+		cfg.NumWorkers = 2
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf(
+			"Two workers of %.0fMB exceed the %dMB memory budget. Add memory or reduce the worker memory.",
+			cfg.WorkerMemoryMB, cfg.AvailableMemoryMB))
+	}
+
+	// max_jobs recycles workers to contain leaks, booting is cheap in rr
+	cfg.MaxJobs = opts.TrafficProfile.MaxRequests
+	trace.set("http.pool.max_jobs", "traffic profile", opts.TrafficProfile.Name, cfg.MaxJobs,
+		"Recycle workers to contain memory leaks")
+
+	// The supervisor restarts a worker once it grows past its fair share
+	share := max(cfg.AvailableMemoryMB/cfg.NumWorkers, 1)
+	cfg.MaxWorkerMemoryMB = int(math.Ceil(cfg.WorkerMemoryMB * 2))
+	trace.set("http.pool.supervisor.max_worker_memory", "worker memory x 2",
+		fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB), cfg.MaxWorkerMemoryMB, "Restart workers that leak memory")
+	if cfg.MaxWorkerMemoryMB > share {
+		trace.adjust("http.pool.supervisor.max_worker_memory", "<= available / num_workers",
+			cfg.MaxWorkerMemoryMB, share, "All workers at the limit must still fit into the memory budget")
+		cfg.MaxWorkerMemoryMB = share
+	}
+	// Below its booted size the supervisor would restart every worker
+	if floor := int(math.Ceil(cfg.WorkerMemoryMB)); cfg.MaxWorkerMemoryMB < floor {
+		trace.adjust("http.pool.supervisor.max_worker_memory", ">= worker memory", cfg.MaxWorkerMemoryMB, floor,
+			"A worker is restarted as soon as it exceeds the limit")
+		cfg.MaxWorkerMemoryMB = floor
+	}
+
+	addRoadRunnerRecommendations(cfg, sysInfo)
+
+	advice := adviseMemorySafety(sysInfo, phpInfo, cfg.AvailableMemoryMB)
+	cfg.Warnings = append(cfg.Warnings, advice.Warnings...)
+	cfg.Recommendations = append(cfg.Recommendations, advice.Recommendations...)
+
+	return cfg
+}
+
+func addRoadRunnerRecommendations(cfg *RoadRunnerConfig, sysInfo *system.Info) {
+	if sysInfo.MemTotalMB < 1024 {
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Low memory system detected. Monitor memory usage closely.")
+	}
+
+	cfg.Recommendations = append(cfg.Recommendations,
+		"Reset state between requests (e.g. Laravel Octane, Spiral) so long-running workers do not leak.")
+
+	cfg.Recommendations = append(cfg.Recommendations,
+		"Set http.pool.allocate_timeout and destroy_timeout so stuck workers are replaced.")
+}
@@ -0,0 +1,274 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range with its own expected traffic
+type Window struct {
+	Start   int     // First hour of the window (0-23)
+	End     int     // Hour the window ends, exclusive; End <= Start wraps past midnight
+	Profile Profile // Traffic pattern during the window
+	RPS     float64 // Expected requests per second (0 = unknown)
+}
+
+// String returns the window as HH-HH
+func (w Window) String() string {
+	return fmt.Sprintf("%02d-%02d", w.Start, w.End)
+}
+
+// Hours returns the hours of the day the window covers
+func (w Window) Hours() []int {
+	var hours []int
+	for h := w.Start; ; h = (h + 1) % 24 {
+		hours = append(hours, h)
+		if (h+1)%24 == w.End%24 {
+			return hours
+		}
+	}
+}
+
+// Concurrency estimates the requests in flight from the expected rate and
+// the mean response time (Little's law)
+func (w Window) Concurrency(latency time.Duration) int {
+	if w.RPS <= 0 {
+		return 0
+	}
+	return max(int(math.Ceil(w.RPS*latency.Seconds())), 1)
+}
+
+// Traffic describes the window's traffic for tables and traces
+func (w Window) Traffic() string {
+	if w.RPS > 0 {
+		return fmt.Sprintf("%s, %g rps", w.Profile.Name, w.RPS)
+	}
+	return w.Profile.Name
+}
+
+// CheckWindows requires the windows to cover every hour of the day exactly
+// once, so there is always one config in effect
+func CheckWindows(windows []Window) error {
+	var owner [24]*Window
+	for i := range windows {
+		w := &windows[i]
+		if w.Start < 0 || w.Start > 23 || w.End < 0 || w.End > 24 {
+			return fmt.Errorf("window %s: hours must be between 0 and 24", w)
+		}
+		for _, h := range w.Hours() {
+			if owner[h] != nil {
+				return fmt.Errorf("windows %s and %s overlap at %02d:00", owner[h], w, h)
+			}
+			owner[h] = w
+		}
+	}
+
+	var missing []string
+	for h, w := range owner {
+		if w == nil {
+			missing = append(missing, fmt.Sprintf("%02d:00", h))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schedule does not cover %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Schedule holds the PHP-FPM config for each window and one config that
+// covers the whole day
+type Schedule struct {
+	Windows []Window
+	Configs []*Config
+	Day     *Config
+}
+
+// CalculateSchedule computes a config per window; windows with an expected
+// rate are capped near the concurrency it needs at the given latency
+func CalculateSchedule(in Input, opts Options, windows []Window, latency time.Duration) *Schedule {
+	s := &Schedule{Windows: windows}
+	for _, w := range windows {
+		o := opts
+		o.TrafficProfile = w.Profile
+		if c := w.Concurrency(latency); c > 0 {
+			o.PlateauWorkers = c
+		}
+		s.Configs = append(s.Configs, Calculate(in, o))
+	}
+
+	// Memory often caps max_children alike, the spares still tell the
+	// profiles apart
+	size := func(i int) [2]int { return [2]int{s.Configs[i].MaxChildren, s.Configs[i].MaxSpareServers} }
+	peak, quiet := 0, 0
+	for i := range s.Configs {
+		if a, b := size(i), size(peak); a[0] > b[0] || a[0] == b[0] && a[1] > b[1] {
+			peak = i
+		}
+		if a, b := size(i), size(quiet); a[0] < b[0] || a[0] == b[0] && a[1] < b[1] {
+			quiet = i
+		}
+	}
+	s.Day = fpmDay(s, peak, quiet, latency)
+	return s
+}
+
+// fpmDay sizes max_children for the busiest window and lets dynamic PM
+// shrink to the quietest window's spares in between
+func fpmDay(s *Schedule, peak, quiet int, latency time.Duration) *Config {
+	p, q := s.Configs[peak], s.Configs[quiet]
+	pw, qw := s.Windows[peak], s.Windows[quiet]
+
+	day := *p
+	day.Warnings = scheduleWarnings(s.Windows, latency, "workers", func(i int) int { return s.Configs[i].MaxChildren })
+	day.Recommendations = []string{}
+	day.Trace = nil
+	trace := &day.Trace
+
+	if peak == quiet {
+		trace.set("pm.max_children", "same in all windows", pw.String(), day.MaxChildren,
+			"Every window needs the same pool, one config covers the day")
+		day.Recommendations = append(day.Recommendations,
+			"All windows lead to the same pool, a schedule brings no benefit.")
+		return &day
+	}
+
+	day.PM = PMDynamic
+	trace.set("pm", "windows differ", fmt.Sprintf("%s, %s", pw, qw), day.PM,
+		"Dynamic PM grows and shrinks the pool between the windows")
+	trace.set("pm.max_children", "peak window", fmt.Sprintf("%s (%s)", pw, pw.Traffic()), day.MaxChildren,
+		"Enough workers for the busiest window")
+
+	day.MinSpareServers = max(q.MinSpareServers, 1)
+	day.MaxSpareServers = max(p.MaxSpareServers, day.MinSpareServers)
+	day.StartServers = min(max(q.StartServers, day.MinSpareServers), day.MaxSpareServers)
+	trace.set("pm.min_spare_servers", "quiet window", fmt.Sprintf("%s (%s)", qw, qw.Traffic()),
+		day.MinSpareServers, "Idle workers kept during the quietest window")
+	trace.set("pm.max_spare_servers", "peak window", fmt.Sprintf("%s (%s)", pw, pw.Traffic()),
+		day.MaxSpareServers, "Idle workers kept ready for the busiest window")
+	trace.set("pm.start_servers", "quiet window", qw.String(), day.StartServers, "Workers forked at startup")
+
+	day.ProcessIdleTimeout = q.ProcessIdleTimeout
+	trace.set("pm.process_idle_timeout", "quiet window", qw.String(), day.ProcessIdleTimeout,
+		"Used by ondemand only, kept for switching PM")
+
+	day.Recommendations = append(day.Recommendations, fmt.Sprintf(
+		"Memory is sized for %d workers during %s; dynamic PM kills idle workers above %d in quieter hours.",
+		day.MaxChildren, pw, day.MaxSpareServers))
+	return &day
+}
+
+// FrankenPHPSchedule holds the FrankenPHP config for each window and one
+// config that covers the whole day
+type FrankenPHPSchedule struct {
+	Windows []Window
+	Configs []*FrankenPHPConfig
+	Day     *FrankenPHPConfig
+}
+
+// CalculateFrankenPHPSchedule computes a config per window and a day config
+// that starts the quietest window's threads and scales up to the busiest
+func CalculateFrankenPHPSchedule(in Input, opts FrankenPHPOptions, windows []Window, latency time.Duration) *FrankenPHPSchedule {
+	s := &FrankenPHPSchedule{Windows: windows}
+	for _, w := range windows {
+		o := opts
+		o.TrafficProfile = w.Profile
+		c := w.Concurrency(latency)
+		if c > 0 {
+			o.PlateauThreads = c
+		}
+		cfg := CalculateFrankenPHP(in, o)
+		raiseMaxThreads(cfg, c)
+		s.Configs = append(s.Configs, cfg)
+	}
+
+	size := func(i int) [2]int {
+		return [2]int{max(s.Configs[i].MaxThreads, s.Configs[i].NumThreads), s.Configs[i].NumThreads}
+	}
+	peak, quiet := 0, 0
+	for i := range s.Configs {
+		if a, b := size(i), size(peak); a[0] > b[0] || a[0] == b[0] && a[1] > b[1] {
+			peak = i
+		}
+		if a, b := size(i), size(quiet); a[1] < b[1] || a[1] == b[1] && a[0] < b[0] {
+			quiet = i
+		}
+	}
+	p, q := s.Configs[peak], s.Configs[quiet]
+	pw, qw := windows[peak], windows[quiet]
+
+	day := *p
+	day.Warnings = scheduleWarnings(windows, latency, "threads", func(i int) int {
+		return max(s.Configs[i].MaxThreads, s.Configs[i].NumThreads)
+	})
+	day.Recommendations = []string{}
+	day.Trace = nil
+	trace := &day.Trace
+
+	day.NumThreads = q.NumThreads
+	day.MaxThreads = max(p.MaxThreads, p.NumThreads)
+	trace.set("num_threads", "quiet window", fmt.Sprintf("%s (%s)", qw, qw.Traffic()), day.NumThreads,
+		"Threads started for the quietest window")
+	trace.set("max_threads", "peak window", fmt.Sprintf("%s (%s)", pw, pw.Traffic()), day.MaxThreads,
+		"FrankenPHP adds threads up to this bound for the busiest window")
+
+	// Both windows allocate the same worker scripts in the same order
+	day.Workers = make([]Worker, len(p.Workers))
+	day.WorkerNum = 0
+	sumMax := 0
+	for i, worker := range p.Workers {
+		worker.MaxThreads = max(worker.MaxThreads, worker.Num)
+		if i < len(q.Workers) {
+			worker.Num = q.Workers[i].Num
+			worker.MaxThreads = max(worker.MaxThreads, worker.Num)
+		}
+		day.Workers[i] = worker
+		day.WorkerNum += worker.Num
+		sumMax += worker.MaxThreads
+	}
+	day.MaxThreads = max(day.MaxThreads, sumMax)
+	if len(day.Workers) > 0 {
+		trace.set("worker.num", "quiet window", qw.String(), day.WorkerNum, "Worker threads kept booted all day")
+	}
+
+	if peak == quiet {
+		day.Recommendations = append(day.Recommendations,
+			"All windows lead to the same threads, a schedule brings no benefit.")
+	} else if day.MaxThreads > day.NumThreads {
+		day.Recommendations = append(day.Recommendations, fmt.Sprintf(
+			"Starts %d threads for %s and scales up to %d during %s; memory is sized for the peak.",
+			day.NumThreads, qw, day.MaxThreads, pw))
+	}
+	s.Day = &day
+	return s
+}
+
+// raiseMaxThreads lets a window with a known rate scale up to the
+// concurrency it expects; threads waiting on I/O only cost memory
+func raiseMaxThreads(cfg *FrankenPHPConfig, concurrency int) {
+	limit := plateauLimit(concurrency)
+	if cfg.ThreadMemoryMB > 0 {
+		limit = min(limit, int(float64(cfg.AvailableMemoryMB)/cfg.ThreadMemoryMB))
+	}
+	if limit <= max(cfg.MaxThreads, cfg.NumThreads) {
+		return
+	}
+	cfg.Trace.adjust("max_threads", "expected concurrency x 1.25", cfg.MaxThreads, limit,
+		fmt.Sprintf("%d requests in flight are expected", concurrency))
+	cfg.MaxThreads = limit
+}
+
+// scheduleWarnings flags windows whose expected concurrency does not fit
+// the workers memory allows
+func scheduleWarnings(windows []Window, latency time.Duration, unit string, workers func(int) int) []string {
+	warnings := []string{}
+	for i, w := range windows {
+		if c := w.Concurrency(latency); c > workers(i) {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s expects %d concurrent requests (%g rps at %s) but memory only fits %d %s",
+				w, c, w.RPS, latency, workers(i), unit))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,170 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// SwooleConfig holds the calculated Swoole/OpenSwoole server settings
+type SwooleConfig struct {
+	ReactorNum    int
+	WorkerNum     int
+	TaskWorkerNum int
+	MaxRequest    int
+
+	// Metadata for display
+	ReservedMemoryMB  int
+	ReservedBreakdown []ReservedItem
+	AvailableMemoryMB int
+	WorkerMemoryMB    float64
+	Warnings          []string
+	Recommendations   []string
+	Trace             Trace
+}
+
+// SwooleOptions for calculation
+type SwooleOptions struct {
+	ReservedMemoryMB int     // Memory reserved for OS/other services
+	WorkerMemoryMB   float64 // Override detected worker memory
+	TrafficProfile   Profile // Expected traffic pattern
+	Coroutine        bool    // Coroutine hooks make blocking I/O asynchronous
+	TaskWorkers      bool    // The app dispatches tasks to task workers
+}
+
+// DefaultSwooleOptions returns sensible defaults
+func DefaultSwooleOptions() SwooleOptions {
+	return SwooleOptions{
+		ReservedMemoryMB: 0, // Auto-calculate
+		WorkerMemoryMB:   0, // Auto-detect
+		TrafficProfile:   DefaultProfile(),
+		Coroutine:        true, // Default for Octane and Hyperf
+	}
+}
+
+// CalculateSwoole computes optimal Swoole/OpenSwoole server settings
+func CalculateSwoole(in Input, opts SwooleOptions) *SwooleConfig {
+	cfg := &SwooleConfig{
+		Warnings:        []string{},
+		Recommendations: []string{},
+	}
+	trace := &cfg.Trace
+	sysInfo, phpInfo := in.System, in.PHP
+
+	// Determine worker memory
+	switch {
+	case opts.WorkerMemoryMB > 0:
+		cfg.WorkerMemoryMB = opts.WorkerMemoryMB
+		trace.set("worker_memory", "user override", "--worker-mem",
+			fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB), "Worker memory given explicitly")
+	case phpInfo != nil && phpInfo.AvgMemoryMB > 0:
+		cfg.WorkerMemoryMB = phpInfo.AvgMemoryMB
+		trace.set("worker_memory", "measured", fmt.Sprintf("average RSS of %d workers", phpInfo.ProcessCount),
+			fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB), "Memory one booted worker uses")
+	default:
+		cfg.WorkerMemoryMB = 50
+		cfg.Warnings = append(cfg.Warnings,
+			"No Swoole workers detected, using 50MB estimate. Use --worker-mem to override.")
+		trace.set("worker_memory", "default estimate", "", "50.0 MB",
+			"Booted framework workers typically use 30-80 MB")
+	}
+
+	cfg.ReservedMemoryMB, cfg.ReservedBreakdown = determineServerReserved(sysInfo,
+		opts.ReservedMemoryMB, in.Services, "OS/Swoole")
+	trace.set("reserved_memory", "sum of reserved items", formatReserved(cfg.ReservedBreakdown),
+		fmt.Sprintf("%d MB", cfg.ReservedMemoryMB), "Memory kept free for the OS, master and other services")
+
+	cfg.AvailableMemoryMB = sysInfo.MemTotalMB - cfg.ReservedMemoryMB
+	trace.set("available_memory", "total - reserved",
+		fmt.Sprintf("%d MB - %d MB", sysInfo.MemTotalMB, cfg.ReservedMemoryMB),
+		fmt.Sprintf("%d MB", cfg.AvailableMemoryMB), "Memory budget for all worker and task processes")
+	if cfg.AvailableMemoryMB < 128 {
+		trace.adjust("available_memory", "minimum 128 MB", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB),
+			"128 MB", "Reservation leaves too little memory, falling back to the minimum")
+		cfg.AvailableMemoryMB = 128
+		cfg.Warnings = append(cfg.Warnings, "Very low available memory, using minimum of 128MB")
+	}
+
+	cpuCores := max(sysInfo.CPUCores, 1)
+	cores := fmt.Sprintf("%d CPU cores", cpuCores)
+	profile := fmt.Sprintf("%s, %s", cores, opts.TrafficProfile.Name)
+
+	// Reactor threads handle network I/O, Swoole's default is one per core
+	cfg.ReactorNum = cpuCores
+	trace.set("reactor_num", "= CPU", cores, cfg.ReactorNum, "One event loop thread per core")
+
+	// With coroutines a worker serves many requests concurrently, so workers
+	// track cores; blocking workers serve one request at a time like FPM
+	factor := 1
+	rule := "CPU x traffic factor (coroutine)"
+	if !opts.Coroutine {
+		factor = 4
+		rule = "CPU x traffic factor (blocking)"
+	}
+	factor = opts.TrafficProfile.scale(factor)
+	cfg.WorkerNum = cpuCores * factor
+	trace.set("worker_num", rule, profile, cfg.WorkerNum, "Worker processes executing PHP")
+
+	if opts.TaskWorkers {
+		cfg.TaskWorkerNum = max(cfg.WorkerNum/2, 1)
+		trace.set("task_worker_num", "worker_num / 2", "", cfg.TaskWorkerNum,
+			"Task workers run dispatched background jobs")
+	}
+
+	maxByMemory := int(math.Floor(float64(cfg.AvailableMemoryMB) / cfg.WorkerMemoryMB))
+	if total := cfg.WorkerNum + cfg.TaskWorkerNum; total > maxByMemory {
+		// Shrink both pools proportionally
+		workers := max(maxByMemory*cfg.WorkerNum/total, 1)
+		tasks := min(cfg.TaskWorkerNum, max(maxByMemory-workers, 0))
+		reason := fmt.Sprintf("Only %d processes fit into %d MB", maxByMemory, cfg.AvailableMemoryMB)
+		trace.adjust("worker_num", "worker + task <= available / worker memory", cfg.WorkerNum, workers, reason)
+		if opts.TaskWorkers {
+			trace.adjust("task_worker_num", "worker + task <= available / worker memory",
+				cfg.TaskWorkerNum, tasks, reason)
+		}
+		cfg.WorkerNum, cfg.TaskWorkerNum = workers, tasks
+		cfg.Warnings = append(cfg.Warnings, "Worker count limited by available memory")
+	}
+
+	// Swoole requires reactor_num <= worker_num
+	if cfg.ReactorNum > cfg.WorkerNum {
+		trace.adjust("reactor_num", "<= worker_num", cfg.ReactorNum, cfg.WorkerNum,
+			"Swoole rejects more reactor threads than workers")
+		cfg.ReactorNum = cfg.WorkerNum
+	}
+
+	// Coroutine workers handle far more requests per lifetime
+	cfg.MaxRequest = 500
+	if opts.Coroutine {
+		cfg.MaxRequest = 10000
+	}
+	trace.set("max_request", "coroutine mode", fmt.Sprint(opts.Coroutine), cfg.MaxRequest,
+		"Recycle workers to contain memory leaks")
+
+	addSwooleRecommendations(cfg, sysInfo, opts)
+
+	advice := adviseMemorySafety(sysInfo, phpInfo, cfg.AvailableMemoryMB)
+	cfg.Warnings = append(cfg.Warnings, advice.Warnings...)
+	cfg.Recommendations = append(cfg.Recommendations, advice.Recommendations...)
+
+	return cfg
+}
+
+func addSwooleRecommendations(cfg *SwooleConfig, sysInfo *system.Info, opts SwooleOptions) {
+	if opts.Coroutine {
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Enable coroutine hooks (SWOOLE_HOOK_ALL) so PDO, Redis and curl calls do not block a worker.")
+	} else {
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Blocking workers serve one request at a time. Coroutines allow far fewer workers.")
+	}
+
+	if sysInfo.MemTotalMB < 1024 {
+		cfg.Recommendations = append(cfg.Recommendations,
+			"Low memory system detected. Monitor memory usage closely.")
+	}
+
+	cfg.Recommendations = append(cfg.Recommendations,
+		"Reset global and static state between requests, workers are long-running.")
+}
@@ -0,0 +1,100 @@
+package calculator
+
+import "fmt"
+
+// SystemdConfig holds the resource limits of a systemd drop-in that make the
+// kernel enforce the budget the calculator assumed
+type SystemdConfig struct {
+	MemoryHighMB   int // Reclaim and throttle above this
+	MemoryMaxMB    int // OOM kill within the unit above this
+	CPUQuotaPct    int
+	LimitNOFILE    int
+	TasksMax       int
+	OOMScoreAdjust int
+
+	Warnings        []string
+	Recommendations []string
+	Trace           Trace
+}
+
+// CalculateSystemdFPM derives the PHP-FPM unit limits from the pool
+func CalculateSystemdFPM(in Input, fpm *Config) *SystemdConfig {
+	cfg := &SystemdConfig{Warnings: []string{}, Recommendations: []string{}}
+	trace := &cfg.Trace
+
+	setMemory(cfg, fpm.AvailableMemoryMB)
+
+	cfg.TasksMax = fpm.MaxChildren*2 + 16
+	trace.set("TasksMax", "max_children x 2 + 16", fmt.Sprint(fpm.MaxChildren), cfg.TasksMax,
+		"Workers, the master and processes PHP spawns")
+
+	setCommon(cfg, in, fpm.MaxChildren)
+	return cfg
+}
+
+// CalculateSystemdFrankenPHP derives the FrankenPHP unit limits from the
+// thread budget
+func CalculateSystemdFrankenPHP(in Input, fp *FrankenPHPConfig) *SystemdConfig {
+	cfg := &SystemdConfig{Warnings: []string{}, Recommendations: []string{}}
+	trace := &cfg.Trace
+
+	threads := max(fp.MaxThreads, fp.NumThreads)
+	setMemory(cfg, fp.AvailableMemoryMB)
+
+	cpuCores := max(in.System.CPUCores, 1)
+	cfg.TasksMax = threads + cpuCores*2 + 64
+	trace.set("TasksMax", "threads + CPU x 2 + 64", fmt.Sprintf("%d threads, %d CPU cores", threads, cpuCores),
+		cfg.TasksMax, "PHP threads plus the Go runtime's own threads")
+
+	setCommon(cfg, in, threads)
+	return cfg
+}
+
+// setMemory throttles the unit at the PHP budget and kills it a margin
+// above, the limits the memory safety advice recommends
+func setMemory(cfg *SystemdConfig, budgetMB int) {
+	trace := &cfg.Trace
+
+	cfg.MemoryHighMB = budgetMB
+	trace.set("MemoryHigh", "available for PHP", "", fmt.Sprintf("%d MB", cfg.MemoryHighMB),
+		"Reclaim and throttle once the budget the workers were sized for is used up")
+
+	cfg.MemoryMaxMB = budgetMB * memoryMaxPercent / 100
+	trace.set("MemoryMax", fmt.Sprintf("MemoryHigh x %d%%", memoryMaxPercent), fmt.Sprintf("%d MB", budgetMB),
+		fmt.Sprintf("%d MB", cfg.MemoryMaxMB), "Headroom for short spikes before the OOM killer steps in")
+}
+
+// setCommon sets the CPU, file and OOM limits shared by all runtimes
+func setCommon(cfg *SystemdConfig, in Input, workers int) {
+	trace := &cfg.Trace
+
+	colocated := 0
+	for _, svc := range in.Services {
+		if svc.ReservedMB() > 0 {
+			colocated++
+		}
+	}
+
+	cpuCores := max(in.System.CPUCores, 1)
+	cfg.CPUQuotaPct = cpuCores * 100
+	trace.set("CPUQuota", "CPU x 100%", fmt.Sprintf("%d CPU cores", cpuCores), fmt.Sprintf("%d%%", cfg.CPUQuotaPct),
+		"All cores the workers were sized for")
+	if colocated > 0 && cpuCores > 1 {
+		trace.adjust("CPUQuota", "- 1 core for services", fmt.Sprintf("%d%%", cfg.CPUQuotaPct),
+			fmt.Sprintf("%d%%", cfg.CPUQuotaPct-100), "Co-located services keep a core under load")
+		cfg.CPUQuotaPct -= 100
+	}
+
+	// systemd's default soft limit of 1024 runs out with sockets, includes
+	// and logs of many workers
+	cfg.LimitNOFILE = max(65536, roundUp(workers*256, 1024))
+	trace.set("LimitNOFILE", "max(65536, workers x 256)", fmt.Sprint(workers), cfg.LimitNOFILE,
+		"File descriptors for connections, files and logs")
+
+	cfg.OOMScoreAdjust = recommendedOOM
+	trace.set("OOMScoreAdjust", "PHP before services", "", cfg.OOMScoreAdjust,
+		"The kernel kills PHP workers before databases and other services")
+
+	cfg.Recommendations = append(cfg.Recommendations,
+		"LimitNOFILE and OOMScoreAdjust only apply after restarting the service.")
+}
@@ -0,0 +1,239 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// violations collects the constraints a generated config breaks
+type violations []error
+
+func (v *violations) check(ok bool, format string, args ...any) {
+	if !ok {
+		*v = append(*v, fmt.Errorf(format, args...))
+	}
+}
+
+func (v violations) err(runtime string) error {
+	if len(v) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid %s configuration: %w", runtime, errors.Join(v...))
+}
+
+// Validate checks the constraints PHP-FPM enforces when loading a pool
+// (fpm_conf.c); a pool that fails them refuses to start
+func (c *Config) Validate() error {
+	var v violations
+
+	v.check(c.MaxChildren >= 1, "pm.max_children(%d) must be a positive value", c.MaxChildren)
+	v.check(c.MaxRequests >= 0, "pm.max_requests(%d) must not be negative", c.MaxRequests)
+
+	switch c.PM {
+	case PMStatic:
+	case PMDynamic:
+		v.check(c.MinSpareServers >= 1, "pm.min_spare_servers(%d) must be a positive value", c.MinSpareServers)
+		v.check(c.MaxSpareServers >= 1, "pm.max_spare_servers(%d) must be a positive value", c.MaxSpareServers)
+		v.check(c.MinSpareServers <= c.MaxSpareServers,
+			"pm.min_spare_servers(%d) must not be greater than pm.max_spare_servers(%d)",
+			c.MinSpareServers, c.MaxSpareServers)
+		v.check(c.MaxSpareServers <= c.MaxChildren,
+			"pm.max_spare_servers(%d) must not be greater than pm.max_children(%d)",
+			c.MaxSpareServers, c.MaxChildren)
+		v.check(c.StartServers >= c.MinSpareServers && c.StartServers <= c.MaxSpareServers,
+			"pm.start_servers(%d) must not be less than pm.min_spare_servers(%d) and not greater than pm.max_spare_servers(%d)",
+			c.StartServers, c.MinSpareServers, c.MaxSpareServers)
+		v.checkDuration("pm.process_idle_timeout", c.ProcessIdleTimeout)
+	case PMOnDemand:
+		v.checkDuration("pm.process_idle_timeout", c.ProcessIdleTimeout)
+	default:
+		v.check(false, "pm(%q) must be static, dynamic or ondemand", c.PM)
+	}
+
+	if c.RequestTerminateTimeout != "" {
+		v.checkDuration("request_terminate_timeout", c.RequestTerminateTimeout)
+	}
+	v.check(c.ListenBacklog >= 0, "listen.backlog(%d) must not be negative", c.ListenBacklog)
+
+	return v.err("php-fpm")
+}
+
+// Validate checks the constraints FrankenPHP enforces on its global options:
+// worker threads are taken from num_threads, which must keep one thread
+// beyond them
+func (c *FrankenPHPConfig) Validate() error {
+	var v violations
+
+	v.check(c.NumThreads >= 1, "num_threads(%d) must be a positive value", c.NumThreads)
+	v.check(c.MaxThreads == 0 || c.MaxThreads >= c.NumThreads,
+		"max_threads(%d) must not be less than num_threads(%d)", c.MaxThreads, c.NumThreads)
+
+	sum, sumMax := 0, 0
+	for _, worker := range c.Workers {
+		name := worker.File
+		if name == "" {
+			name = "worker"
+		}
+		v.check(worker.Num >= 1, "%s num(%d) must be a positive value", name, worker.Num)
+		v.check(worker.MaxThreads == 0 || worker.MaxThreads >= worker.Num,
+			"%s max_threads(%d) must not be less than its num(%d)", name, worker.MaxThreads, worker.Num)
+		sum += worker.Num
+		sumMax += max(worker.MaxThreads, worker.Num)
+	}
+	v.check(sum == c.WorkerNum, "worker num total(%d) does not match the workers(%d)", c.WorkerNum, sum)
+	v.check(sum < c.NumThreads, "num_threads(%d) must be greater than the sum of worker num(%d)", c.NumThreads, sum)
+	v.check(sumMax <= max(c.MaxThreads, c.NumThreads),
+		"max_threads(%d) must be at least the sum of worker max_threads(%d)", max(c.MaxThreads, c.NumThreads), sumMax)
+
+	if c.MaxWaitTime != "" {
+		v.checkDuration("max_wait_time", c.MaxWaitTime)
+	}
+
+	return v.err("frankenphp")
+}
+
+// Validate checks the prefork MPM constraints Apache corrects with a
+// warning at startup, since a silently corrected value is not what we chose
+func (c *ApacheConfig) Validate() error {
+	var v violations
+
+	v.check(c.MaxRequestWorkers >= 1, "MaxRequestWorkers(%d) must be a positive value", c.MaxRequestWorkers)
+	v.check(c.ServerLimit >= c.MaxRequestWorkers,
+		"ServerLimit(%d) must not be less than MaxRequestWorkers(%d)", c.ServerLimit, c.MaxRequestWorkers)
+	v.check(c.MinSpareServers >= 1, "MinSpareServers(%d) must be a positive value", c.MinSpareServers)
+	v.check(c.MaxSpareServers > c.MinSpareServers,
+		"MaxSpareServers(%d) must be greater than MinSpareServers(%d)", c.MaxSpareServers, c.MinSpareServers)
+	v.check(c.StartServers >= 1 && c.StartServers <= c.MaxSpareServers,
+		"StartServers(%d) must be between 1 and MaxSpareServers(%d)", c.StartServers, c.MaxSpareServers)
+	v.check(c.MaxConnectionsPerChild >= 0,
+		"MaxConnectionsPerChild(%d) must not be negative", c.MaxConnectionsPerChild)
+
+	return v.err("apache")
+}
+
+// Validate checks the RoadRunner pool settings
+func (c *RoadRunnerConfig) Validate() error {
+	var v violations
+
+	v.check(c.NumWorkers >= 1, "http.pool.num_workers(%d) must be a positive value", c.NumWorkers)
+	v.check(c.MaxJobs >= 0, "http.pool.max_jobs(%d) must not be negative", c.MaxJobs)
+	v.check(c.MaxWorkerMemoryMB >= 1,
+		"http.pool.supervisor.max_worker_memory(%d) must be a positive value", c.MaxWorkerMemoryMB)
+
+	return v.err("roadrunner")
+}
+
+// Validate checks the constraints Swoole enforces in $server->set()
+func (c *SwooleConfig) Validate() error {
+	var v violations
+
+	v.check(c.WorkerNum >= 1, "worker_num(%d) must be a positive value", c.WorkerNum)
+	v.check(c.ReactorNum >= 1, "reactor_num(%d) must be a positive value", c.ReactorNum)
+	v.check(c.ReactorNum <= c.WorkerNum,
+		"reactor_num(%d) must not be greater than worker_num(%d)", c.ReactorNum, c.WorkerNum)
+	v.check(c.TaskWorkerNum >= 0, "task_worker_num(%d) must not be negative", c.TaskWorkerNum)
+	v.check(c.MaxRequest >= 0, "max_request(%d) must not be negative", c.MaxRequest)
+
+	return v.err("swoole")
+}
+
+// Validate checks the limits systemd would reject or that would kill the
+// service before it reaches the budget
+func (c *SystemdConfig) Validate() error {
+	var v violations
+
+	v.check(c.MemoryHighMB >= 1, "MemoryHigh(%dM) must be a positive value", c.MemoryHighMB)
+	v.check(c.MemoryHighMB <= c.MemoryMaxMB,
+		"MemoryHigh(%dM) must not be greater than MemoryMax(%dM)", c.MemoryHighMB, c.MemoryMaxMB)
+	v.check(c.CPUQuotaPct >= 1, "CPUQuota(%d%%) must be a positive value", c.CPUQuotaPct)
+	v.check(c.LimitNOFILE >= 1024, "LimitNOFILE(%d) must be at least 1024", c.LimitNOFILE)
+	v.check(c.TasksMax >= 1, "TasksMax(%d) must be a positive value", c.TasksMax)
+	v.check(c.OOMScoreAdjust >= -1000 && c.OOMScoreAdjust <= 1000,
+		"OOMScoreAdjust(%d) must be between -1000 and 1000", c.OOMScoreAdjust)
+
+	return v.err("systemd")
+}
+
+// checkDuration accepts the PHP-FPM/Caddy time formats this package emits
+func (v *violations) checkDuration(name, value string) {
+	_, err := ParseDuration(value)
+	v.check(err == nil, "%s(%q) must be a duration like 10s", name, value)
+}
+
+// Time units from the most to the least significant; PHP-FPM and Caddy
+// know s, m, h and d, nginx all of them
+var durationUnits = []struct {
+	suffix string
+	unit   time.Duration
+	nginx  bool
+}{
+	{"y", 365 * 24 * time.Hour, true},
+	{"M", 30 * 24 * time.Hour, true},
+	{"w", 7 * 24 * time.Hour, true},
+	{"d", 24 * time.Hour, false},
+	{"h", time.Hour, false},
+	{"m", time.Minute, false},
+	{"s", time.Second, false},
+	{"ms", time.Millisecond, true},
+}
+
+// ParseDuration parses the time formats this package emits: digits with an
+// optional s, m, h or d suffix, seconds without one (as PHP-FPM reads them)
+func ParseDuration(value string) (time.Duration, error) {
+	return parseDuration(value, false)
+}
+
+// ParseNginxDuration also accepts nginx's ms, w, M and y units and combined
+// values like 1m30s or "1h 30m", largest unit first
+func ParseNginxDuration(value string) (time.Duration, error) {
+	return parseDuration(strings.TrimSpace(value), true)
+}
+
+func parseDuration(value string, nginx bool) (time.Duration, error) {
+	var total time.Duration
+	last := -1
+	rest := value
+	for {
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		n, err := strconv.Atoi(rest[:digits])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		rest = rest[digits:]
+
+		// The longest matching suffix, "ms" before "m"
+		unit := -1
+		for i, u := range durationUnits {
+			if (nginx || !u.nginx) && strings.HasPrefix(rest, u.suffix) &&
+				(unit < 0 || len(u.suffix) > len(durationUnits[unit].suffix)) {
+				unit = i
+			}
+		}
+		if unit < 0 {
+			// Only a bare number, meaning seconds, may omit the unit
+			if rest != "" || last >= 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n) * time.Second, nil
+		}
+		// Units must get smaller, as nginx requires
+		if unit <= last {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * durationUnits[unit].unit
+		rest, last = rest[len(durationUnits[unit].suffix):], unit
+
+		if nginx {
+			rest = strings.TrimLeft(rest, " ")
+		}
+		if rest == "" {
+			return total, nil
+		}
+		if !nginx {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		nginx bool // Only valid for ParseNginxDuration
+		want  time.Duration
+	}{
+		{"30", false, 30 * time.Second},
+		{"30s", false, 30 * time.Second},
+		{"5m", false, 5 * time.Minute},
+		{"1h", false, time.Hour},
+		{"2d", false, 48 * time.Hour},
+		{"1m30s", true, 90 * time.Second},
+		{"1h 30m", true, 90 * time.Minute},
+		{"1m500ms", true, time.Minute + 500*time.Millisecond},
+		{"1500ms", true, 1500 * time.Millisecond},
+		{"2w", true, 14 * 24 * time.Hour},
+		{"1M", true, 30 * 24 * time.Hour},
+		{"1y", true, 365 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got, err := ParseNginxDuration(tt.value); err != nil || got != tt.want {
+			t.Errorf("ParseNginxDuration(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+		got, err := ParseDuration(tt.value)
+		switch {
+		case tt.nginx && err == nil:
+			t.Errorf("ParseDuration(%q) accepted an nginx-only time", tt.value)
+		case !tt.nginx && (err != nil || got != tt.want):
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "s", "30x", "30s1m", "1m1m", "1m30", "-5s", "+5"} {
+		if _, err := ParseNginxDuration(value); err == nil {
+			t.Errorf("ParseNginxDuration(%q) accepted an invalid time", value)
+		}
+	}
+}
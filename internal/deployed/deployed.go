@@ -0,0 +1,204 @@
+package deployed
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	fpmPoolPaths = []string{
+		"/etc/php/*/fpm/pool.d/www.conf",
+		"/etc/php-fpm.d/www.conf",
+		"/etc/opt/remi/php*/php-fpm.d/www.conf",
+		"/usr/local/etc/php-fpm.d/www.conf",
+	}
+	caddyfilePaths = []string{
+		"/etc/frankenphp/Caddyfile",
+		"/etc/caddy/Caddyfile",
+		"Caddyfile",
+	}
+)
+
+// ErrNotFound is returned when no deployed config file could be located
+var ErrNotFound = errors.New("no deployed configuration found")
+
+// FindFPMPool returns the first PHP-FPM pool file in the standard locations
+func FindFPMPool() (string, error) {
+	return findFirst(fpmPoolPaths)
+}
+
+// FindCaddyfile returns the first Caddyfile in the standard locations
+func FindCaddyfile() (string, error) {
+	return findFirst(caddyfilePaths)
+}
+
+func findFirst(patterns []string) (string, error) {
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		if len(matches) > 0 {
+			// Highest PHP version sorts last
+			return matches[len(matches)-1], nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// ParseFPMPool reads the pm.*, listen* and request_terminate_timeout
+// directives of a pool; an empty pool name selects the first pool in the file
+func ParseFPMPool(path, pool string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	directives := map[string]string{}
+	current := ""
+	found := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.Trim(line, "[]")
+			if pool == "" && !found && current != "global" {
+				pool = current
+			}
+			found = found || current == pool
+			continue
+		}
+
+		if current != pool {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "pm" || strings.HasPrefix(name, "pm.") || strings.HasPrefix(name, "listen") ||
+			name == "request_terminate_timeout" {
+			directives[name] = strings.Trim(strings.TrimSpace(stripINIComment(value)), `"'`)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("pool %q not found in %s", pool, path)
+	}
+
+	return directives, nil
+}
+
+// stripINIComment removes a trailing ; comment outside double quotes, as
+// PHP's INI parser does
+func stripINIComment(value string) string {
+	quoted := false
+	for i, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			return value[:i]
+		}
+	}
+	return value
+}
+
+// ParseCaddyfile reads the frankenphp global options block; worker
+// settings of the first worker are prefixed with "worker.", except
+// worker.num which sums the threads of all workers
+func ParseCaddyfile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	directives := map[string]string{}
+	var stack []string
+	found := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(line)
+
+		if fields[len(fields)-1] == "{" {
+			stack = append(stack, fields[0])
+			found = found || fields[0] == "frankenphp"
+			continue
+		}
+
+		if line == "}" {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		block := strings.Join(stack, "/")
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(block, "frankenphp") && fields[0] == "worker":
+			// Short form: worker <file> [<num>]
+			setOnce(directives, "worker.file", fields[1])
+			if len(fields) > 2 {
+				addCount(directives, "worker.num", fields[2])
+			}
+		case strings.HasSuffix(block, "frankenphp"):
+			directives[fields[0]] = fields[1]
+		case strings.HasSuffix(block, "/worker") && fields[0] == "num":
+			addCount(directives, "worker.num", fields[1])
+		case strings.HasSuffix(block, "/worker"):
+			setOnce(directives, "worker."+fields[0], fields[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no frankenphp block in %s", path)
+	}
+
+	return directives, nil
+}
+
+// addCount sums a numeric directive over several blocks, e.g. the threads
+// of all workers
+func addCount(directives map[string]string, name, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		setOnce(directives, name, value)
+		return
+	}
+	total, _ := strconv.Atoi(directives[name])
+	directives[name] = strconv.Itoa(total + n)
+}
+
+func setOnce(directives map[string]string, name, value string) {
+	if _, ok := directives[name]; !ok {
+		directives[name] = value
+	}
+}
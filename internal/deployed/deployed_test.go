@@ -0,0 +1,68 @@
+package deployed
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseFPMPool(t *testing.T) {
+	const conf = `; Pool defaults
+[global]
+pm.max_children = 1
+
+[www]
+listen = /run/php/php8.3-fpm.sock
+pm = dynamic ; default
+pm.max_children = 5 ; default
+pm.start_servers=2
+pm.min_spare_servers = "1"
+pm.max_spare_servers = '3'
+pm.status_path = "/status;fpm" ; quoted semicolon
+request_terminate_timeout = 30s ; hard limit
+# listen.backlog = 511
+user = www-data
+
+[api]
+pm = static
+pm.max_children = 20
+`
+	path := filepath.Join(t.TempDir(), "www.conf")
+	if err := os.WriteFile(path, []byte(conf), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pool string
+		want map[string]string
+	}{
+		{"", map[string]string{
+			"listen":                    "/run/php/php8.3-fpm.sock",
+			"pm":                        "dynamic",
+			"pm.max_children":           "5",
+			"pm.start_servers":          "2",
+			"pm.min_spare_servers":      "1",
+			"pm.max_spare_servers":      "3",
+			"pm.status_path":            "/status;fpm",
+			"request_terminate_timeout": "30s",
+		}},
+		{"api", map[string]string{
+			"pm":              "static",
+			"pm.max_children": "20",
+		}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFPMPool(path, tt.pool)
+		if err != nil {
+			t.Fatalf("ParseFPMPool(%q): %v", tt.pool, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFPMPool(%q) = %v, want %v", tt.pool, got, tt.want)
+		}
+	}
+
+	if _, err := ParseFPMPool(path, "missing"); err == nil {
+		t.Error("ParseFPMPool accepted a missing pool")
+	}
+}
@@ -0,0 +1,140 @@
+package deployed
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetFPMDirective sets a directive in a pool section, replacing an existing
+// line or appending it to the end of the section
+func SetFPMDirective(path, pool, name, value string) error {
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	insertAt := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			if current == pool && insertAt >= 0 {
+				break
+			}
+			current = strings.Trim(trimmed, "[]")
+			if pool == "" && current != "global" {
+				pool = current
+			}
+			if current == pool {
+				insertAt = i + 1
+			}
+			continue
+		}
+
+		if current != pool || trimmed == "" || trimmed[0] == ';' || trimmed[0] == '#' {
+			continue
+		}
+		insertAt = i + 1
+
+		key, _, ok := strings.Cut(trimmed, "=")
+		if ok && strings.TrimSpace(key) == name {
+			lines[i] = name + " = " + value
+			return writeLines(path, lines)
+		}
+	}
+
+	if insertAt < 0 {
+		return fmt.Errorf("pool %q not found in %s", pool, path)
+	}
+
+	lines = append(lines[:insertAt], append([]string{name + " = " + value}, lines[insertAt:]...)...)
+	return writeLines(path, lines)
+}
+
+// SetCaddyfileDirective sets a directive in the frankenphp global options
+// block, replacing an existing line or adding it after the opening brace
+func SetCaddyfileDirective(path, name, value string) error {
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	var stack []string
+	openedAt := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(trimmed)
+		if fields[len(fields)-1] == "{" {
+			stack = append(stack, fields[0])
+			if fields[0] == "frankenphp" && openedAt < 0 {
+				openedAt = i
+			}
+			continue
+		}
+		if trimmed == "}" {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1] == "frankenphp" && fields[0] == name {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + name + " " + value
+			return writeLines(path, lines)
+		}
+	}
+
+	if openedAt < 0 {
+		return fmt.Errorf("no frankenphp block in %s", path)
+	}
+
+	opening := lines[openedAt]
+	indent := opening[:len(opening)-len(strings.TrimLeft(opening, " \t"))] + "\t"
+	lines = append(lines[:openedAt+1], append([]string{indent + name + " " + value}, lines[openedAt+1:]...)...)
+	return writeLines(path, lines)
+}
+
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// writeLines replaces the file atomically so a running server never reads
+// a half-written config
+func writeLines(path string, lines []string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// Change compares one deployed directive with its recommended value
+type Change struct {
+	Directive   string
+	Current     string  // Empty when the directive is not set
+	Recommended string  // Empty when only the deployed config sets it
+	Delta       float64 // Recommended - current for numeric values
+	Numeric     bool
+	Equivalent  bool    // Same value in another notation, e.g. 10 and 10s
+	DriftPct    float64 // Relative change in percent, 100 for other changed values, 0 when missing on either side
+	MemoryMB    float64 // Projected change of peak memory usage
+}
+
+// Changed reports whether the deployed value differs from the recommendation
+func (c Change) Changed() bool {
+	return c.Current != c.Recommended && !c.Equivalent
+}
+
+// Missing reports whether the deployed config leaves the directive unset
+func (c Change) Missing() bool {
+	return c.Current == "" && c.Recommended != ""
+}
+
+// Extra reports whether only the deployed config sets the directive, e.g.
+// spare servers on a static pool
+func (c Change) Extra() bool {
+	return c.Recommended == "" && c.Current != ""
+}
+
+// Directives the calculator tunes; other deployed settings are not compared
+var (
+	fpmTuned = []string{
+		"pm", "pm.max_children", "pm.start_servers", "pm.min_spare_servers", "pm.max_spare_servers",
+		"pm.process_idle_timeout", "pm.max_requests", "request_terminate_timeout", "listen.backlog",
+	}
+	frankenphpTuned = []string{"num_threads", "max_threads", "max_wait_time", "worker.num"}
+)
+
+// FPM compares a deployed pool with the recommended config
+func FPM(current map[string]string, cfg *calculator.Config) []Change {
+	// Every worker above the old limit may use the full process memory
+	perUnit := map[string]float64{"pm.max_children": cfg.ProcessMemoryMB}
+	return compare(current, cfg.Directives(), fpmTuned, perUnit)
+}
+
+// FrankenPHP compares a deployed Caddyfile with the recommended config
+func FrankenPHP(current map[string]string, cfg *calculator.FrankenPHPConfig) []Change {
+	// Scaling threads are the peak, so only count num_threads when max_threads is absent
+	perUnit := map[string]float64{"max_threads": cfg.ThreadMemoryMB}
+	if cfg.MaxThreads <= cfg.NumThreads {
+		perUnit = map[string]float64{"num_threads": cfg.ThreadMemoryMB}
+	}
+	return compare(current, cfg.Directives(), frankenphpTuned, perUnit)
+}
+
+// MaxDrift returns the largest drift in percent across all changes
+func MaxDrift(changes []Change) float64 {
+	drift := 0.0
+	for _, c := range changes {
+		drift = math.Max(drift, c.DriftPct)
+	}
+	return drift
+}
+
+// MemoryImpact returns the total projected memory change in MB
+func MemoryImpact(changes []Change) float64 {
+	total := 0.0
+	for _, c := range changes {
+		total += c.MemoryMB
+	}
+	return total
+}
+
+func compare(current map[string]string, recommended []calculator.Directive, tuned []string, perUnit map[string]float64) []Change {
+	changes := make([]Change, 0, len(recommended))
+	seen := map[string]bool{}
+
+	for _, d := range recommended {
+		seen[d.Name] = true
+		c := Change{Directive: d.Name, Current: current[d.Name], Recommended: d.Value}
+
+		cur, errCur := strconv.ParseFloat(c.Current, 64)
+		rec, errRec := strconv.ParseFloat(c.Recommended, 64)
+		curTime, okCur := seconds(c.Current)
+		recTime, okRec := seconds(c.Recommended)
+
+		switch {
+		case !c.Changed() || c.Missing():
+		case errCur == nil && errRec == nil:
+			c.Numeric = true
+			c.Delta = rec - cur
+			c.DriftPct = relative(c.Delta, cur)
+			c.MemoryMB = c.Delta * perUnit[d.Name]
+		case okCur && okRec:
+			c.Equivalent = curTime == recTime
+			c.DriftPct = relative(recTime-curTime, curTime)
+		default:
+			c.DriftPct = 100
+		}
+
+		changes = append(changes, c)
+	}
+
+	// Leftovers the recommendation drops, sorted for stable output
+	var extra []string
+	for _, name := range tuned {
+		if !seen[name] && current[name] != "" {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		changes = append(changes, Change{Directive: name, Current: current[name]})
+	}
+
+	return changes
+}
+
+func relative(delta, base float64) float64 {
+	if base == 0 {
+		return 100
+	}
+	return math.Abs(delta) / base * 100
+}
+
+// seconds parses PHP-FPM (10, 10s, 1d) and Go (1m30s) durations
+func seconds(value string) (float64, bool) {
+	if d, err := calculator.ParseDuration(value); err == nil {
+		return d.Seconds(), true
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return d.Seconds(), true
+	}
+	return 0, false
+}
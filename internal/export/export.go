@@ -0,0 +1,171 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// Format is the file format of an export
+type Format string
+
+// Export file formats
+const (
+	YAML   Format = "yaml"   // Ansible group_vars, Helm values
+	JSON   Format = "json"   // Terraform .tfvars.json, anything else
+	TFVars Format = "tfvars" // Terraform .tfvars
+)
+
+// Mapping maps calculated values, named like the directives of the config
+// (pm.max_children, num_threads, limits.memory, ...), to target keys. Dots
+// in a target key nest it, e.g. resources.limits.memory.
+type Mapping struct {
+	Name   string            `json:"-"`
+	Format Format            `json:"format"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// Built-in mappings to common variable names
+var builtins = map[string]Mapping{
+	// geerlingguy.php names, the rest follows the same scheme
+	"ansible": {Name: "ansible", Format: YAML, Keys: map[string]string{
+		"pm":                        "php_fpm_pm",
+		"pm.max_children":           "php_fpm_pm_max_children",
+		"pm.start_servers":          "php_fpm_pm_start_servers",
+		"pm.min_spare_servers":      "php_fpm_pm_min_spare_servers",
+		"pm.max_spare_servers":      "php_fpm_pm_max_spare_servers",
+		"pm.process_idle_timeout":   "php_fpm_pm_process_idle_timeout",
+		"pm.max_requests":           "php_fpm_pm_max_requests",
+		"request_terminate_timeout": "php_fpm_request_terminate_timeout",
+		"listen.backlog":            "php_fpm_listen_backlog",
+		"num_threads":               "frankenphp_num_threads",
+		"max_threads":               "frankenphp_max_threads",
+		"max_wait_time":             "frankenphp_max_wait_time",
+		"worker.num":                "frankenphp_worker_num",
+	}},
+	"terraform": {Name: "terraform", Format: TFVars, Keys: map[string]string{
+		"pm":                        "php_fpm_pm",
+		"pm.max_children":           "php_fpm_max_children",
+		"pm.start_servers":          "php_fpm_start_servers",
+		"pm.min_spare_servers":      "php_fpm_min_spare_servers",
+		"pm.max_spare_servers":      "php_fpm_max_spare_servers",
+		"pm.process_idle_timeout":   "php_fpm_process_idle_timeout",
+		"pm.max_requests":           "php_fpm_max_requests",
+		"request_terminate_timeout": "php_fpm_request_terminate_timeout",
+		"listen.backlog":            "php_fpm_listen_backlog",
+		"num_threads":               "frankenphp_num_threads",
+		"max_threads":               "frankenphp_max_threads",
+		"max_wait_time":             "frankenphp_max_wait_time",
+		"worker.num":                "frankenphp_worker_num",
+		"limits.cpu":                "cpu_limit",
+		"limits.memory":             "memory_limit",
+	}},
+	// resources.limits is the layout of virtually every chart
+	"helm": {Name: "helm", Format: YAML, Keys: map[string]string{
+		"pm":                        "phpFpm.pm",
+		"pm.max_children":           "phpFpm.maxChildren",
+		"pm.start_servers":          "phpFpm.startServers",
+		"pm.min_spare_servers":      "phpFpm.minSpareServers",
+		"pm.max_spare_servers":      "phpFpm.maxSpareServers",
+		"pm.process_idle_timeout":   "phpFpm.processIdleTimeout",
+		"pm.max_requests":           "phpFpm.maxRequests",
+		"request_terminate_timeout": "phpFpm.requestTerminateTimeout",
+		"listen.backlog":            "phpFpm.listenBacklog",
+		"num_threads":               "frankenphp.numThreads",
+		"max_threads":               "frankenphp.maxThreads",
+		"max_wait_time":             "frankenphp.maxWaitTime",
+		"worker.num":                "frankenphp.workerNum",
+		"limits.cpu":                "resources.limits.cpu",
+		"limits.memory":             "resources.limits.memory",
+	}},
+}
+
+// Targets returns the names of the built-in mappings
+func Targets() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Lookup returns a built-in mapping
+func Lookup(name string) (Mapping, error) {
+	m, ok := builtins[strings.ToLower(name)]
+	if !ok {
+		return m, fmt.Errorf("unknown export target %q (available: %s)", name, strings.Join(Targets(), ", "))
+	}
+	return m, nil
+}
+
+// LoadMapping reads a user-defined mapping from a JSON file:
+//
+//	{"format": "yaml", "keys": {"pm.max_children": "php.fpm.children"}}
+func LoadMapping(path string) (Mapping, error) {
+	m := Mapping{Name: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return m, fmt.Errorf("%s: %w", path, err)
+	}
+	if m.Format == "" {
+		m.Format = YAML
+	}
+	if err := m.Validate(); err != nil {
+		return m, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Validate rejects unknown formats and keys that cannot be nested
+func (m Mapping) Validate() error {
+	switch m.Format {
+	case YAML, JSON, TFVars:
+	default:
+		return fmt.Errorf("unknown format %q (use yaml, json or tfvars)", m.Format)
+	}
+	if len(m.Keys) == 0 {
+		return fmt.Errorf("no keys mapped")
+	}
+
+	targets := make([]string, 0, len(m.Keys))
+	for value, key := range m.Keys {
+		if key == "" || slices.Contains(strings.Split(key, "."), "") {
+			return fmt.Errorf("invalid key %q for %s", key, value)
+		}
+		targets = append(targets, key)
+	}
+
+	// a.b next to a.b.c would make a.b both a value and a section
+	slices.Sort(targets)
+	for i := 1; i < len(targets); i++ {
+		if targets[i] == targets[i-1] || strings.HasPrefix(targets[i], targets[i-1]+".") {
+			return fmt.Errorf("key %q conflicts with %q", targets[i], targets[i-1])
+		}
+	}
+	return nil
+}
+
+// Values returns the directives of a config followed by the container limits
+// limits.cpu (cores) and limits.memory (Kubernetes quantity)
+func Values(directives []calculator.Directive, limits *calculator.SystemdConfig) []calculator.Directive {
+	values := slices.Clone(directives)
+	if limits != nil {
+		values = append(values,
+			calculator.Directive{Name: "limits.cpu", Value: fmt.Sprintf("%g", float64(limits.CPUQuotaPct)/100)},
+			calculator.Directive{Name: "limits.memory", Value: fmt.Sprintf("%dMi", limits.MemoryMaxMB)},
+		)
+	}
+	return values
+}
@@ -0,0 +1,116 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// node is a key of the export, either a value or a section
+type node struct {
+	key      string
+	value    string
+	children []*node
+}
+
+func (n *node) child(key string) *node {
+	for _, c := range n.children {
+		if c.key == key {
+			return c
+		}
+	}
+	c := &node{key: key}
+	n.children = append(n.children, c)
+	return c
+}
+
+// Write renders the mapped values, values without a key are left out.
+// Keys keep the order of the values.
+func Write(w io.Writer, m Mapping, values []calculator.Directive) error {
+	root := &node{}
+	for _, v := range values {
+		key, ok := m.Keys[v.Name]
+		if !ok {
+			continue
+		}
+		n := root
+		for _, part := range strings.Split(key, ".") {
+			n = n.child(part)
+		}
+		n.value = v.Value
+	}
+
+	switch m.Format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root.json())
+	case TFVars:
+		for _, c := range root.children {
+			writeTFVars(w, c, "")
+		}
+	default:
+		for _, c := range root.children {
+			writeYAML(w, c, "")
+		}
+	}
+	return nil
+}
+
+func (n *node) json() any {
+	if n.children == nil {
+		if number(n.value) {
+			return json.Number(n.value)
+		}
+		return n.value
+	}
+	obj := make(map[string]any, len(n.children))
+	for _, c := range n.children {
+		obj[c.key] = c.json()
+	}
+	return obj
+}
+
+func writeYAML(w io.Writer, n *node, indent string) {
+	if n.children == nil {
+		fmt.Fprintf(w, "%s%s: %s\n", indent, n.key, scalar(n.value))
+		return
+	}
+	fmt.Fprintf(w, "%s%s:\n", indent, n.key)
+	for _, c := range n.children {
+		writeYAML(w, c, indent+"  ")
+	}
+}
+
+func writeTFVars(w io.Writer, n *node, indent string) {
+	if n.children == nil {
+		fmt.Fprintf(w, "%s%s = %s\n", indent, n.key, scalar(n.value))
+		return
+	}
+	fmt.Fprintf(w, "%s%s = {\n", indent, n.key)
+	for _, c := range n.children {
+		writeTFVars(w, c, indent+"  ")
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+}
+
+// scalar leaves numbers bare and quotes everything else; a JSON string is
+// valid in YAML and HCL alike
+func scalar(value string) string {
+	if number(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func number(value string) bool {
+	if value == "" || value[0] < '0' || value[0] > '9' {
+		return false
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+func TestWrite(t *testing.T) {
+	keys := map[string]string{
+		"pm":                        "php.pm",
+		"pm.max_children":           "php.max_children",
+		"request_terminate_timeout": "php.timeout",
+		"limits.cpu":                "resources.limits.cpu",
+		"limits.memory":             "resources.limits.memory",
+		"listen.backlog":            "backlog",
+	}
+	values := []calculator.Directive{
+		{Name: "pm", Value: "dynamic"},
+		{Name: "pm.max_children", Value: "12"},
+		{Name: "pm.start_servers", Value: "3"}, // Not mapped
+		{Name: "request_terminate_timeout", Value: "30s"},
+		{Name: "listen.backlog", Value: "511"},
+		{Name: "limits.cpu", Value: "1.5"},
+		{Name: "limits.memory", Value: "512Mi"},
+	}
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{YAML, `php:
+  pm: "dynamic"
+  max_children: 12
+  timeout: "30s"
+backlog: 511
+resources:
+  limits:
+    cpu: 1.5
+    memory: "512Mi"
+`},
+		{TFVars, `php = {
+  pm = "dynamic"
+  max_children = 12
+  timeout = "30s"
+}
+backlog = 511
+resources = {
+  limits = {
+    cpu = 1.5
+    memory = "512Mi"
+  }
+}
+`},
+		{JSON, `{
+  "backlog": 511,
+  "php": {
+    "max_children": 12,
+    "pm": "dynamic",
+    "timeout": "30s"
+  },
+  "resources": {
+    "limits": {
+      "cpu": 1.5,
+      "memory": "512Mi"
+    }
+  }
+}
+`},
+	}
+	for _, tt := range tests {
+		m := Mapping{Format: tt.format, Keys: keys}
+		if err := m.Validate(); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		var buf bytes.Buffer
+		if err := Write(&buf, m, values); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping Mapping
+		valid   bool
+	}{
+		{"nested", Mapping{Format: YAML, Keys: map[string]string{"pm": "php.fpm.pm", "num_threads": "php.threads"}}, true},
+		{"value and section", Mapping{Format: JSON, Keys: map[string]string{"pm": "php.pm", "pm.max_children": "php.pm.children"}}, false},
+		{"duplicate", Mapping{Format: TFVars, Keys: map[string]string{"pm": "php", "num_threads": "php"}}, false},
+		{"shared prefix", Mapping{Format: YAML, Keys: map[string]string{"pm": "php.pm", "pm.max_children": "php.pmx"}}, true},
+		{"empty part", Mapping{Format: YAML, Keys: map[string]string{"pm": "php..pm"}}, false},
+		{"empty key", Mapping{Format: YAML, Keys: map[string]string{"pm": ""}}, false},
+		{"no keys", Mapping{Format: YAML}, false},
+		{"unknown format", Mapping{Format: "toml", Keys: map[string]string{"pm": "pm"}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.mapping.Validate(); (err == nil) != tt.valid {
+			t.Errorf("%s: Validate() = %v, want valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Runtimes supported by the exporter
+const (
+	RuntimeFPM        = "php-fpm"
+	RuntimeFrankenPHP = "frankenphp"
+)
+
+const mb = 1024 * 1024
+
+// Config controls what the exporter measures
+type Config struct {
+	Runtime       string
+	Interval      time.Duration
+	Current       string // Deployed config file, empty disables configured_* metrics
+	Pool          string // PHP-FPM pool to read the configured values from
+	FPM           calculator.Options
+	FrankenPHP    calculator.FrankenPHPOptions
+	Services      []services.Service // Detected once at startup
+	MemoryLimitMB int                // PHP memory_limit (0 = unknown)
+}
+
+// Exporter periodically recomputes the tuner's view and serves it as metrics
+type Exporter struct {
+	cfg Config
+
+	mu   sync.RWMutex
+	body []byte
+}
+
+// New creates an exporter
+func New(cfg Config) *Exporter {
+	return &Exporter{cfg: cfg}
+}
+
+// Run refreshes the metrics immediately and then on every interval until ctx is done
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Refresh(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not refresh metrics: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ServeHTTP serves the most recently refreshed metrics
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	body := e.body
+	e.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(body)
+}
+
+// Refresh re-detects the host and recomputes all metrics
+func (e *Exporter) Refresh(ctx context.Context) error {
+	sysInfo, err := system.Detect()
+	if err != nil {
+		return err
+	}
+
+	m := newMetricWriter()
+	m.gauge("php_tuner_cpu_cores", "Detected CPU cores.", float64(sysInfo.CPUCores))
+	m.gauge("php_tuner_memory_total_bytes", "Total system memory.", float64(sysInfo.MemTotalMB)*mb)
+
+	switch e.cfg.Runtime {
+	case RuntimeFrankenPHP:
+		err = e.collectFrankenPHP(ctx, m, sysInfo)
+	default:
+		err = e.collectFPM(ctx, m, sysInfo)
+	}
+	if err != nil {
+		return err
+	}
+
+	m.gauge("php_tuner_last_refresh_timestamp_seconds", "Unix time of the last successful refresh.",
+		float64(time.Now().Unix()))
+
+	e.mu.Lock()
+	e.body = m.bytes()
+	e.mu.Unlock()
+
+	return nil
+}
+
+func (e *Exporter) collectFPM(ctx context.Context, m *metricWriter, sysInfo *system.Info) error {
+	procs, err := php.DetectProcesses(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg := calculator.Calculate(calculator.Input{System: sysInfo, PHP: procs, Services: e.cfg.Services,
+		MemoryLimitMB: e.cfg.MemoryLimitMB}, e.cfg.FPM)
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	m.gauge("php_tuner_recommended_max_children", "Recommended pm.max_children.", float64(cfg.MaxChildren))
+	if e.cfg.Current != "" {
+		current, err := deployed.ParseFPMPool(e.cfg.Current, e.cfg.Pool)
+		if err != nil {
+			return err
+		}
+		if val, err := strconv.Atoi(current["pm.max_children"]); err == nil {
+			m.gauge("php_tuner_configured_max_children", "Deployed pm.max_children.", float64(val))
+		}
+	}
+
+	writeMemory(m, sysInfo, procs, cfg.AvailableMemoryMB, cfg.ReservedBreakdown)
+	return nil
+}
+
+func (e *Exporter) collectFrankenPHP(ctx context.Context, m *metricWriter, sysInfo *system.Info) error {
+	procs, err := php.DetectProcessesMatching(ctx, "frankenphp")
+	if err != nil {
+		return err
+	}
+
+	cfg := calculator.CalculateFrankenPHP(calculator.Input{System: sysInfo, Services: e.cfg.Services}, e.cfg.FrankenPHP)
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	m.gauge("php_tuner_recommended_num_threads", "Recommended num_threads.", float64(cfg.NumThreads))
+	m.gauge("php_tuner_recommended_max_threads", "Recommended max_threads.", float64(cfg.MaxThreads))
+	if e.cfg.Current != "" {
+		current, err := deployed.ParseCaddyfile(e.cfg.Current)
+		if err != nil {
+			return err
+		}
+		if val, err := strconv.Atoi(current["num_threads"]); err == nil {
+			m.gauge("php_tuner_configured_num_threads", "Deployed num_threads.", float64(val))
+		}
+		if val, err := strconv.Atoi(current["max_threads"]); err == nil {
+			m.gauge("php_tuner_configured_max_threads", "Deployed max_threads.", float64(val))
+		}
+	}
+
+	writeMemory(m, sysInfo, procs, cfg.AvailableMemoryMB, cfg.ReservedBreakdown)
+	return nil
+}
+
+// writeMemory writes budget, headroom, risk, reserved and per-pool PSS metrics.
+// Usage is the PSS total, summed RSS counts shared pages once per process.
+func writeMemory(m *metricWriter, sysInfo *system.Info, procs *php.ProcessInfo, budgetMB int,
+	reserved []calculator.ReservedItem) {
+	pools := map[string][]float64{}
+	var usedBytes float64
+	for _, proc := range procs.Processes {
+		pool := proc.Pool
+		if pool == "" {
+			pool = proc.Command
+		}
+		pss := proc.PSSKB
+		if pss == 0 {
+			pss = proc.MemoryKB // smaps_rollup needs Linux 4.14+
+		}
+		pools[pool] = append(pools[pool], float64(pss)*1024)
+		usedBytes += float64(pss) * 1024
+	}
+	usedMB := usedBytes / mb
+
+	m.gauge("php_tuner_memory_budget_bytes", "Memory available to PHP after reservations.", float64(budgetMB)*mb)
+	m.gauge("php_tuner_memory_used_bytes", "Proportional set size of all PHP processes.", usedBytes)
+	m.gauge("php_tuner_memory_headroom_bytes", "Memory budget minus PHP usage.",
+		(float64(budgetMB)-usedMB)*mb)
+	m.gauge("php_tuner_oom_risk_score", "Estimated OOM kill risk from 0 (safe) to 1 (imminent).",
+		calculator.OOMRiskScore(sysInfo, usedMB, budgetMB))
+	m.gauge("php_tuner_processes", "Detected PHP processes.", float64(procs.ProcessCount))
+
+	for _, item := range reserved {
+		m.gauge("php_tuner_reserved_memory_bytes", "Memory reserved for the OS and co-located services.",
+			float64(item.MemoryMB)*mb, "item", item.Label)
+	}
+
+	for _, pool := range slices.Sorted(maps.Keys(pools)) {
+		for _, q := range []float64{50, 90, 99} {
+			m.gauge("php_tuner_pool_pss_bytes", "Per-pool process PSS percentiles.",
+				percentile(pools[pool], q), "pool", pool, "quantile", strconv.FormatFloat(q/100, 'g', -1, 64))
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricWriter renders gauges in the Prometheus text exposition format
+type metricWriter struct {
+	buf  bytes.Buffer
+	seen map[string]bool
+}
+
+func newMetricWriter() *metricWriter {
+	return &metricWriter{seen: map[string]bool{}}
+}
+
+// gauge writes one sample; labels are name/value pairs
+func (m *metricWriter) gauge(name, help string, value float64, labels ...string) {
+	if !m.seen[name] {
+		m.seen[name] = true
+		fmt.Fprintf(&m.buf, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	m.buf.WriteString(name)
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, labels[i]+"="+strconv.Quote(labels[i+1]))
+		}
+		m.buf.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	m.buf.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+func (m *metricWriter) bytes() []byte {
+	return m.buf.Bytes()
+}
+
+// percentile returns the nearest-rank percentile of values (0 < p <= 100)
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
@@ -0,0 +1,200 @@
+package fastcgi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record types from the FastCGI specification
+const (
+	typeBeginRequest = 1
+	typeEndRequest   = 3
+	typeParams       = 4
+	typeStdin        = 5
+	typeStdout       = 6
+	typeStderr       = 7
+
+	roleResponder = 1
+	version       = 1
+	requestID     = 1
+	maxContent    = 65535
+)
+
+// Response is the parsed CGI response of a FastCGI request
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	Stderr []byte
+}
+
+// Client sends single requests over fresh connections, as PHP-FPM closes
+// the connection after each request unless keep-alive is negotiated
+type Client struct {
+	Network string // "tcp" or "unix"
+	Address string
+	Timeout time.Duration
+}
+
+// NewClient parses an address like "127.0.0.1:9000" or "unix:/run/php/php-fpm.sock"
+func NewClient(addr string, timeout time.Duration) *Client {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		return &Client{Network: "unix", Address: path, Timeout: timeout}
+	}
+	if strings.HasPrefix(addr, "/") {
+		return &Client{Network: "unix", Address: addr, Timeout: timeout}
+	}
+	return &Client{Network: "tcp", Address: addr, Timeout: timeout}
+}
+
+// Do sends a request with the given CGI params and body
+func (c *Client) Do(ctx context.Context, params map[string]string, body []byte) (*Response, error) {
+	dialer := net.Dialer{Timeout: c.Timeout}
+	conn, err := dialer.DialContext(ctx, c.Network, c.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else if c.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(c.Timeout))
+	}
+
+	w := bufio.NewWriter(conn)
+
+	// Role responder, flags 0 (close connection after the request)
+	begin := []byte{0, roleResponder, 0, 0, 0, 0, 0, 0}
+	if err := writeRecord(w, typeBeginRequest, begin); err != nil {
+		return nil, err
+	}
+
+	if err := writeStream(w, typeParams, encodeParams(params)); err != nil {
+		return nil, err
+	}
+	if err := writeStream(w, typeStdin, body); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return readResponse(bufio.NewReader(conn))
+}
+
+// writeStream writes data in chunks followed by the empty terminating record
+func writeStream(w io.Writer, recType byte, data []byte) error {
+	for len(data) > 0 {
+		n := min(len(data), maxContent)
+		if err := writeRecord(w, recType, data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return writeRecord(w, recType, nil)
+}
+
+func writeRecord(w io.Writer, recType byte, content []byte) error {
+	padding := (8 - len(content)%8) % 8
+
+	header := [8]byte{version, recType}
+	binary.BigEndian.PutUint16(header[2:], requestID)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(content)))
+	header[6] = byte(padding)
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(content); err != nil {
+		return err
+	}
+	_, err := w.Write(make([]byte, padding))
+	return err
+}
+
+func encodeParams(params map[string]string) []byte {
+	var buf bytes.Buffer
+	for name, value := range params {
+		writeLength(&buf, len(name))
+		writeLength(&buf, len(value))
+		buf.WriteString(name)
+		buf.WriteString(value)
+	}
+	return buf.Bytes()
+}
+
+// writeLength uses one byte for lengths below 128, four bytes otherwise
+func writeLength(buf *bytes.Buffer, n int) {
+	if n < 128 {
+		buf.WriteByte(byte(n))
+		return
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(n)|1<<31)
+	buf.Write(b[:])
+}
+
+func readResponse(r *bufio.Reader) (*Response, error) {
+	var stdout, stderr bytes.Buffer
+
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, fmt.Errorf("reading record: %w", err)
+		}
+
+		length := int(binary.BigEndian.Uint16(header[4:]))
+		content := make([]byte, length+int(header[6]))
+		if _, err := io.ReadFull(r, content); err != nil {
+			return nil, fmt.Errorf("reading record: %w", err)
+		}
+		content = content[:length]
+
+		switch header[1] {
+		case typeStdout:
+			stdout.Write(content)
+		case typeStderr:
+			stderr.Write(content)
+		case typeEndRequest:
+			resp, err := parseCGI(stdout.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			resp.Stderr = stderr.Bytes()
+			return resp, nil
+		}
+	}
+}
+
+// parseCGI splits the CGI headers from the body and extracts the status
+func parseCGI(data []byte) (*Response, error) {
+	tp := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+
+	header, err := tp.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid CGI headers: %w", err)
+	}
+
+	resp := &Response{Status: http.StatusOK, Header: http.Header(header)}
+	if status := header.Get("Status"); status != "" {
+		code, _, _ := strings.Cut(status, " ")
+		if resp.Status, err = strconv.Atoi(code); err != nil {
+			return nil, fmt.Errorf("invalid CGI status %q", status)
+		}
+	}
+
+	resp.Body, _ = io.ReadAll(tp.R)
+	return resp, nil
+}
@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Framework is a PHP framework with a known worker-mode integration
+type Framework string
+
+const (
+	None          Framework = ""
+	LaravelOctane Framework = "laravel-octane"
+	Symfony       Framework = "symfony"
+)
+
+// Parse converts a --framework value, accepting a few common aliases
+func Parse(value string) (Framework, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return None, nil
+	case "laravel", "octane", "laravel-octane":
+		return LaravelOctane, nil
+	case "symfony", "symfony-runtime":
+		return Symfony, nil
+	}
+	return None, fmt.Errorf("unknown framework %q (use laravel-octane or symfony)", value)
+}
+
+// Detect inspects composer.lock in the project directory. It returns None
+// when the project uses neither Laravel Octane nor the Symfony Runtime.
+func Detect(projectDir string) (Framework, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, "composer.lock"))
+	if err != nil {
+		return None, err
+	}
+
+	var lock struct {
+		Packages []struct {
+			Name string `json:"name"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return None, fmt.Errorf("composer.lock: %w", err)
+	}
+
+	names := make([]string, 0, len(lock.Packages))
+	for _, pkg := range lock.Packages {
+		names = append(names, pkg.Name)
+	}
+
+	switch {
+	case slices.Contains(names, "laravel/octane"):
+		return LaravelOctane, nil
+	case slices.Contains(names, "runtime/frankenphp-symfony"),
+		slices.Contains(names, "symfony/runtime") && slices.Contains(names, "symfony/framework-bundle"):
+		return Symfony, nil
+	}
+
+	return None, nil
+}
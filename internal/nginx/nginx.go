@@ -0,0 +1,196 @@
+package nginx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var configPaths = []string{
+	"/etc/nginx/nginx.conf",
+	"/usr/local/nginx/conf/nginx.conf",
+	"/usr/local/etc/nginx/nginx.conf",
+}
+
+// ErrNotFound is returned when no nginx.conf could be located
+var ErrNotFound = errors.New("no nginx configuration found")
+
+// Upstream is an upstream block with its servers and idle connection pool
+type Upstream struct {
+	Servers   []string
+	Keepalive int
+}
+
+// Config holds the nginx settings relevant to a PHP-FPM backend
+type Config struct {
+	Path              string
+	WorkerProcesses   string
+	WorkerConnections int
+	FastCGIPass       []string            // Distinct fastcgi_pass targets
+	Upstreams         map[string]Upstream // Keyed by upstream name
+	FastCGI           map[string]string   // First fastcgi_* value, e.g. fastcgi_buffers
+}
+
+// Find returns the first nginx.conf in the standard locations
+func Find() (string, error) {
+	for _, path := range configPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// Parse reads nginx.conf and all files it includes
+func Parse(path string) (*Config, error) {
+	cfg := &Config{
+		Path:      path,
+		Upstreams: map[string]Upstream{},
+		FastCGI:   map[string]string{},
+	}
+
+	p := &parser{cfg: cfg, root: filepath.Dir(path), seen: map[string]bool{}}
+	if err := p.parseFile(path, nil); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+type parser struct {
+	cfg  *Config
+	root string
+	seen map[string]bool
+}
+
+func (p *parser) parseFile(path string, stack []string) error {
+	if p.seen[path] {
+		return nil
+	}
+	p.seen[path] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	for _, token := range tokenize(string(data)) {
+		switch token {
+		case "{":
+			stack = append(stack, strings.Join(args, " "))
+			args = nil
+		case "}":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			args = nil
+		case ";":
+			if err := p.statement(args, stack); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			args = nil
+		default:
+			args = append(args, token)
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) statement(args, stack []string) error {
+	if len(args) < 2 {
+		return nil
+	}
+	name, value := args[0], args[1]
+	block := ""
+	if len(stack) > 0 {
+		block = stack[len(stack)-1]
+	}
+
+	switch {
+	case name == "include":
+		pattern := value
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(p.root, pattern)
+		}
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if err := p.parseFile(match, stack); err != nil {
+				return err
+			}
+		}
+	case name == "worker_processes":
+		p.cfg.WorkerProcesses = value
+	case name == "worker_connections":
+		p.cfg.WorkerConnections, _ = strconv.Atoi(value)
+	case name == "fastcgi_pass":
+		if !slices.Contains(p.cfg.FastCGIPass, value) {
+			p.cfg.FastCGIPass = append(p.cfg.FastCGIPass, value)
+		}
+	case strings.HasPrefix(name, "fastcgi_"):
+		if _, ok := p.cfg.FastCGI[name]; !ok {
+			p.cfg.FastCGI[name] = strings.Join(args[1:], " ")
+		}
+	case strings.HasPrefix(block, "upstream "):
+		upstreamName := strings.TrimPrefix(block, "upstream ")
+		upstream := p.cfg.Upstreams[upstreamName]
+		switch name {
+		case "server":
+			upstream.Servers = append(upstream.Servers, value)
+		case "keepalive":
+			upstream.Keepalive, _ = strconv.Atoi(value)
+		}
+		p.cfg.Upstreams[upstreamName] = upstream
+	}
+
+	return nil
+}
+
+// tokenize splits a config into words, quoted strings, "{", "}" and ";"
+func tokenize(data string) []string {
+	var tokens []string
+	var word strings.Builder
+	var quote rune
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+
+	comment := false
+	for _, r := range data {
+		switch {
+		case comment:
+			comment = r != '\n'
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				flush()
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			flush()
+			comment = true
+		case r == '{' || r == '}' || r == ';':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
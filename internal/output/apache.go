@@ -0,0 +1,87 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+// PrintApacheHeader prints the Apache header
+func (p *Printer) PrintApacheHeader() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "Apache Prefork Optimizer (mod_php)"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+}
+
+// PrintApacheInfo displays detected Apache children
+func (p *Printer) PrintApacheInfo(info *php.ProcessInfo) {
+	p.printProcessInfo("Apache Processes", "No apache2/httpd processes detected",
+		"Using estimates based on php.ini memory_limit", "Child Count", info)
+}
+
+// PrintApacheCalculation displays the Apache calculation summary
+func (p *Printer) PrintApacheCalculation(cfg *calculator.ApacheConfig) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "Calculation"))
+	fmt.Fprintln(p.w)
+
+	p.printRow("Reserved Memory", fmt.Sprintf("%d MB (for OS/services)", cfg.ReservedMemoryMB))
+	p.printReservedBreakdown(cfg.ReservedBreakdown)
+	p.printRow("Available for PHP", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB))
+	p.printRow("Child Memory", fmt.Sprintf("%.1f MB", cfg.ProcessMemoryMB))
+	p.printRow("Formula", fmt.Sprintf("%d MB / %.1f MB = %d children",
+		cfg.AvailableMemoryMB, cfg.ProcessMemoryMB, cfg.MaxRequestWorkers))
+	fmt.Fprintln(p.w)
+}
+
+// PrintApacheConfig displays the recommended mpm_prefork.conf
+func (p *Printer) PrintApacheConfig(cfg *calculator.ApacheConfig) {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended Configuration"))
+		fmt.Fprintln(p.w)
+	}
+
+	fmt.Fprintln(p.w, "<IfModule mpm_prefork_module>")
+	for _, d := range cfg.Directives() {
+		fmt.Fprintf(p.w, "    %-24s %s\n", d.Name, d.Value)
+	}
+	fmt.Fprintln(p.w, "</IfModule>")
+
+	if !p.onlyConf {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// PrintApacheWarnings displays Apache warnings
+func (p *Printer) PrintApacheWarnings(cfg *calculator.ApacheConfig) {
+	p.printWarnings(cfg.Warnings)
+}
+
+// PrintApacheRecommendations displays Apache recommendations
+func (p *Printer) PrintApacheRecommendations(cfg *calculator.ApacheConfig) {
+	p.printRecommendations(cfg.Recommendations)
+}
+
+// PrintApacheUsage displays how to apply the Apache configuration
+func (p *Printer) PrintApacheUsage() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  1. Replace the prefork settings:")
+	fmt.Fprintln(p.w, p.color(Dim, "     /etc/apache2/mods-available/mpm_prefork.conf"))
+	fmt.Fprintln(p.w, p.color(Dim, "     or /etc/httpd/conf.modules.d/00-mpm.conf"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  2. Restart Apache (ServerLimit needs a full restart):")
+	fmt.Fprintln(p.w, p.color(Dim, "     sudo systemctl restart apache2"))
+	fmt.Fprintln(p.w)
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/autotune"
+)
+
+// PrintAutotuneHistory displays every step recorded in an autotune state file
+func (p *Printer) PrintAutotuneHistory(state *autotune.State) {
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "Autotune History"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+
+	if len(state.Steps) == 0 {
+		fmt.Fprintln(p.w, p.color(Dim, "  No steps recorded"))
+		fmt.Fprintln(p.w)
+		return
+	}
+
+	p.printRow("Config", state.Config)
+	p.printRow("Directive", state.Directive)
+	fmt.Fprintln(p.w)
+
+	rows := [][]string{{"started", "change", "outcome", "busy before", "busy after", "latency"}}
+	for _, s := range state.Steps {
+		after, latency := "-", "-"
+		if s.After != nil {
+			after = fmt.Sprintf("%.0f%%", s.After.PeakSaturationPct)
+			if s.After.LatencyMS > 0 {
+				latency = fmt.Sprintf("%.0f -> %.0f ms", s.Before.LatencyMS, s.After.LatencyMS)
+			}
+		}
+		rows = append(rows, []string{
+			s.Started.Local().Format("2006-01-02 15:04"),
+			fmt.Sprintf("%d -> %d", s.From, s.To),
+			s.Outcome,
+			fmt.Sprintf("%.0f%%", s.Before.PeakSaturationPct),
+			after,
+			latency,
+		})
+	}
+	p.printTable(rows, func(i int) string {
+		switch state.Steps[i].Outcome {
+		case autotune.OutcomeKept:
+			return Green
+		case autotune.OutcomeReverted:
+			return Yellow
+		case autotune.OutcomeAbandoned:
+			return Red
+		}
+		return ""
+	})
+	fmt.Fprintln(p.w)
+
+	fmt.Fprintln(p.w, p.color(Bold, "Reasons"))
+	fmt.Fprintln(p.w)
+	for i, s := range state.Steps {
+		fmt.Fprintf(p.w, "  %2d. %s\n", i+1, s.Reason)
+	}
+	fmt.Fprintln(p.w)
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/bench"
+)
+
+// PrintBenchHeader prints the benchmark header
+func (p *Printer) PrintBenchHeader(target string) {
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "Load Test"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+	p.printRow("Target", target)
+	fmt.Fprintln(p.w)
+}
+
+// PrintBenchStep prints the progress line of a finished step
+func (p *Printer) PrintBenchStep(step bench.Step) {
+	fmt.Fprintf(p.w, "  %s concurrency %d: %.1f req/s, p99 %s, %.1f%% errors\n",
+		p.color(Dim, "»"), step.Concurrency, step.Throughput, formatLatency(step.P99), step.ErrorRate())
+}
+
+// PrintBenchResult displays the ramp results and the measured plateau
+func (p *Printer) PrintBenchResult(result *bench.Result, flag string) {
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold, "Results"))
+	fmt.Fprintln(p.w)
+
+	rows := [][]string{{"concurrency", "req/s", "p50", "p90", "p99", "errors", "workers", "worker mem"}}
+	for _, s := range result.Steps {
+		workers, mem := "-", "-"
+		if s.WorkerCount > 0 {
+			workers = fmt.Sprint(s.WorkerCount)
+			mem = fmt.Sprintf("%.0f MB", s.WorkerMemMB)
+		}
+		rows = append(rows, []string{fmt.Sprint(s.Concurrency), fmt.Sprintf("%.1f", s.Throughput),
+			formatLatency(s.P50), formatLatency(s.P90), formatLatency(s.P99),
+			fmt.Sprintf("%.1f%%", s.ErrorRate()), workers, mem})
+	}
+	p.printTable(rows, func(i int) string {
+		if result.Steps[i].Concurrency == result.Plateau {
+			return Green
+		}
+		return ""
+	})
+	fmt.Fprintln(p.w)
+
+	p.printRow("Peak Throughput", fmt.Sprintf("%.1f req/s", result.PeakThroughput))
+	if result.Plateau == 0 {
+		p.printRow("Plateau", p.color(Yellow, "not reached, extend the ramp"))
+		fmt.Fprintln(p.w)
+		return
+	}
+
+	p.printRow("Plateau", fmt.Sprintf("%d concurrent requests", result.Plateau))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  Feed the plateau back into the calculator:")
+	fmt.Fprintln(p.w, p.color(Dim, fmt.Sprintf("     php-tuner %s --plateau %d", flag, result.Plateau)))
+	fmt.Fprintln(p.w)
+}
+
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
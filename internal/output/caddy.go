@@ -0,0 +1,53 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/caddy"
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// PrintFrankenPHPCaddyJSON prints the frankenphp app as Caddy JSON, either a
+// full config or the admin API payload of the app alone
+func (p *Printer) PrintFrankenPHPCaddyJSON(cfg *calculator.FrankenPHPConfig, workerMode, payload bool) error {
+	app, err := caddy.NewApp(cfg, workerMode, "/path/to/your/public/index.php")
+	if err != nil {
+		return err
+	}
+
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended Configuration"))
+		fmt.Fprintln(p.w)
+	}
+
+	var v any = caddy.NewConfig(app)
+	if payload {
+		v = app
+	}
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+
+	if p.onlyConf {
+		return nil
+	}
+	fmt.Fprintln(p.w)
+
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	if payload {
+		fmt.Fprintln(p.w, "  Replace the frankenphp app of the running Caddy:")
+		fmt.Fprintln(p.w, p.color(Dim, "     curl -X PATCH -H 'Content-Type: application/json' \\"))
+		fmt.Fprintln(p.w, p.color(Dim, "          -d @frankenphp.json http://localhost:2019"+caddy.AppPath))
+		fmt.Fprintln(p.w, p.color(Dim, "     # or: php-tuner f --caddy-admin http://localhost:2019"))
+	} else {
+		fmt.Fprintln(p.w, "  Merge the frankenphp app into your Caddy JSON config, then:")
+		fmt.Fprintln(p.w, p.color(Dim, "     curl -X POST -H 'Content-Type: application/json' \\"))
+		fmt.Fprintln(p.w, p.color(Dim, "          -d @caddy.json http://localhost:2019/load"))
+	}
+	fmt.Fprintln(p.w)
+	return nil
+}
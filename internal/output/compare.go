@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Comparison is the recommendation for one hypothetical machine shape;
+// exactly one of FPM or FrankenPHP is set
+type Comparison struct {
+	Shape      string
+	System     *system.Info
+	FPM        *calculator.Config
+	FrankenPHP *calculator.FrankenPHPConfig
+}
+
+// PrintComparison tabulates recommended configs across machine shapes
+func (p *Printer) PrintComparison(rows []Comparison) {
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "Machine Comparison"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+
+	var table [][]string
+	if rows[0].FPM != nil {
+		table = append(table, []string{"shape", "cpu", "memory", "reserved", "available",
+			"pm", "max_children", "start", "min_spare", "max_spare"})
+		for _, r := range rows {
+			c := r.FPM
+			table = append(table, []string{r.Shape, fmt.Sprint(r.System.CPUCores),
+				fmt.Sprintf("%d MB", r.System.MemTotalMB), fmt.Sprintf("%d MB", c.ReservedMemoryMB),
+				fmt.Sprintf("%d MB", c.AvailableMemoryMB), string(c.PM), fmt.Sprint(c.MaxChildren),
+				fmt.Sprint(c.StartServers), fmt.Sprint(c.MinSpareServers), fmt.Sprint(c.MaxSpareServers)})
+		}
+	} else {
+		table = append(table, []string{"shape", "cpu", "memory", "reserved", "available",
+			"num_threads", "max_threads", "worker_num", "max_wait_time"})
+		for _, r := range rows {
+			c := r.FrankenPHP
+			wait := c.MaxWaitTime
+			if wait == "" {
+				wait = "-"
+			}
+			table = append(table, []string{r.Shape, fmt.Sprint(r.System.CPUCores),
+				fmt.Sprintf("%d MB", r.System.MemTotalMB), fmt.Sprintf("%d MB", c.ReservedMemoryMB),
+				fmt.Sprintf("%d MB", c.AvailableMemoryMB), fmt.Sprint(c.NumThreads),
+				fmt.Sprint(c.MaxThreads), fmt.Sprint(c.WorkerNum), wait})
+		}
+	}
+
+	p.printTable(table, nil)
+	fmt.Fprintln(p.w)
+}
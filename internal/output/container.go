@@ -0,0 +1,120 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// Official FrankenPHP image and the app directory it serves
+const (
+	frankenphpImage = "dunglas/frankenphp"
+	containerWorker = "/app/public/index.php"
+)
+
+// Docker ENV values cannot contain line breaks, the Dockerfile imports the
+// options from this file instead
+const containerOptionsFile = "/etc/frankenphp/php-tuner.caddyfile"
+
+// Container describes how the FrankenPHP image is configured: env for an
+// env_file, dockerfile or compose
+type Container struct {
+	Format     string
+	ServerName string                    // SERVER_NAME, empty keeps the image default
+	Limits     *calculator.SystemdConfig // Memory and CPU limits of the container
+}
+
+// PrintFrankenPHPContainer prints the configuration as environment of the
+// official image, which reads it into its Caddyfile
+func (p *Printer) PrintFrankenPHPContainer(cfg *calculator.FrankenPHPConfig, workerMode bool, c Container) {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended Configuration"))
+		fmt.Fprintln(p.w)
+	}
+
+	options := frankenphpOptions(cfg, workerMode, containerWorker)
+	env := containerEnv(cfg, c)
+
+	switch c.Format {
+	case "env":
+		fmt.Fprintf(p.w, "FRANKENPHP_CONFIG=%s\n", strconv.Quote(strings.Join(options, "\n")))
+		for _, e := range env {
+			fmt.Fprintf(p.w, "%s=%s\n", e.Name, strconv.Quote(e.Value))
+		}
+
+	case "dockerfile":
+		fmt.Fprintln(p.w, "# syntax=docker/dockerfile:1")
+		fmt.Fprintf(p.w, "FROM %s\n", frankenphpImage)
+		fmt.Fprintln(p.w)
+		fmt.Fprintf(p.w, "ENV FRANKENPHP_CONFIG=%s\n", strconv.Quote("import "+containerOptionsFile))
+		for _, e := range env {
+			fmt.Fprintf(p.w, "ENV %s=%s\n", e.Name, strconv.Quote(e.Value))
+		}
+		// Quoted delimiter, the options are copied without expansion
+		fmt.Fprintf(p.w, "COPY <<'EOF' %s\n", containerOptionsFile)
+		for _, line := range options {
+			fmt.Fprintln(p.w, line)
+		}
+		fmt.Fprintln(p.w, "EOF")
+
+	case "compose":
+		fmt.Fprintln(p.w, "services:")
+		fmt.Fprintln(p.w, "  php:")
+		fmt.Fprintf(p.w, "    image: %s\n", frankenphpImage)
+		fmt.Fprintln(p.w, "    environment:")
+		fmt.Fprintln(p.w, "      FRANKENPHP_CONFIG: |")
+		for _, line := range options {
+			fmt.Fprintln(p.w, "        "+line)
+		}
+		for _, e := range env {
+			fmt.Fprintf(p.w, "      %s: %s\n", e.Name, strconv.Quote(e.Value))
+		}
+		if c.Limits != nil {
+			cpus, memory := containerLimits(c.Limits)
+			fmt.Fprintln(p.w, "    deploy:")
+			fmt.Fprintln(p.w, "      resources:")
+			fmt.Fprintln(p.w, "        limits:")
+			fmt.Fprintf(p.w, "          cpus: %q\n", cpus)
+			fmt.Fprintf(p.w, "          memory: %s\n", memory)
+		}
+	}
+
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w)
+
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	switch c.Format {
+	case "env":
+		fmt.Fprintln(p.w, "  Save as .env and reference it from compose.yaml:")
+		fmt.Fprintln(p.w, p.color(Dim, "     env_file: .env"))
+	case "dockerfile":
+		fmt.Fprintln(p.w, "  Build the image (requires BuildKit) and run it with the limits:")
+		if c.Limits != nil {
+			cpus, memory := containerLimits(c.Limits)
+			fmt.Fprintln(p.w, p.color(Dim, fmt.Sprintf("     docker run --cpus %s --memory %s ...", cpus, memory)))
+		}
+	case "compose":
+		fmt.Fprintln(p.w, "  Merge the php service into compose.yaml, then:")
+		fmt.Fprintln(p.w, p.color(Dim, "     docker compose up -d"))
+	}
+	fmt.Fprintln(p.w)
+}
+
+// containerEnv returns SERVER_NAME and the framework's environment
+func containerEnv(cfg *calculator.FrankenPHPConfig, c Container) []calculator.Directive {
+	var env []calculator.Directive
+	if c.ServerName != "" {
+		env = append(env, calculator.Directive{Name: "SERVER_NAME", Value: c.ServerName})
+	}
+	return append(env, cfg.Env...)
+}
+
+// containerLimits formats the limits for docker, e.g. "3.5" CPUs and 2048M
+func containerLimits(limits *calculator.SystemdConfig) (string, string) {
+	return strconv.FormatFloat(float64(limits.CPUQuotaPct)/100, 'f', -1, 64), fmt.Sprintf("%dM", limits.MemoryMaxMB)
+}
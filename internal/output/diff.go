@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/diff"
+)
+
+// PrintDiff displays deployed vs recommended settings per directive
+func (p *Printer) PrintDiff(path string, changes []diff.Change, thresholdPct float64) {
+	fmt.Fprintln(p.w, p.color(Bold+Green, "Current vs Recommended"))
+	fmt.Fprintln(p.w, p.color(Dim, "  "+path))
+	fmt.Fprintln(p.w)
+
+	rows := [][]string{{"directive", "current", "recommended", "delta", "memory"}}
+	for _, c := range changes {
+		current, recommended := c.Current, c.Recommended
+		if current == "" {
+			current = "(unset)"
+		}
+		if recommended == "" {
+			recommended = "(unset)"
+		}
+
+		delta, memory := "", ""
+		if c.Numeric {
+			delta = fmt.Sprintf("%+g", c.Delta)
+		}
+		if c.MemoryMB != 0 {
+			memory = fmt.Sprintf("%+.0f MB", c.MemoryMB)
+		}
+
+		rows = append(rows, []string{c.Directive, current, recommended, delta, memory})
+	}
+
+	p.printTable(rows, func(i int) string {
+		switch {
+		case !changes[i].Changed():
+			return Dim
+		case changes[i].Missing() || changes[i].Extra():
+			return Yellow
+		case changes[i].DriftPct > thresholdPct:
+			return Red
+		default:
+			return Yellow
+		}
+	})
+	fmt.Fprintln(p.w)
+
+	drift := diff.MaxDrift(changes)
+	p.printRow("Memory Impact", fmt.Sprintf("%+.0f MB at peak", diff.MemoryImpact(changes)))
+	driftText := fmt.Sprintf("%.0f%% (threshold %.0f%%)", drift, thresholdPct)
+	if drift > thresholdPct {
+		driftText = p.color(Red, driftText)
+	}
+	p.printRow("Max Drift", driftText)
+	fmt.Fprintln(p.w)
+}
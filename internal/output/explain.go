@@ -0,0 +1,44 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// PrintExplanation displays every decision the calculator made as a step list
+func (p *Printer) PrintExplanation(trace calculator.Trace) {
+	if p.onlyConf || len(trace) == 0 {
+		return
+	}
+
+	fmt.Fprintln(p.w, p.color(Bold+Magenta, "Explanation"))
+	fmt.Fprintln(p.w)
+
+	for i, d := range trace {
+		if d.Before != "" {
+			fmt.Fprintf(p.w, "  %2d. %s: %s -> %s\n", i+1, p.color(Bold, d.Field), d.Before, p.color(Green, d.After))
+		} else {
+			fmt.Fprintf(p.w, "  %2d. %s = %s\n", i+1, p.color(Bold, d.Field), p.color(Green, d.After))
+		}
+
+		rule := d.Rule
+		if d.Input != "" {
+			rule += " (" + d.Input + ")"
+		}
+		fmt.Fprintf(p.w, "      %s %s\n", p.color(Dim, "rule:"), rule)
+		fmt.Fprintf(p.w, "      %s\n", p.color(Dim, d.Reason))
+	}
+	fmt.Fprintln(p.w)
+}
+
+// PrintExplanationJSON writes the decision trace as JSON
+func (p *Printer) PrintExplanationJSON(runtime string, trace calculator.Trace) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Runtime   string           `json:"runtime"`
+		Decisions calculator.Trace `json:"decisions"`
+	}{runtime, trace})
+}
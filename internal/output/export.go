@@ -0,0 +1,23 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/export"
+)
+
+// PrintExport prints the values as variables for infrastructure code
+func (p *Printer) PrintExport(m export.Mapping, values []calculator.Directive) error {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Export ("+m.Name+")"))
+		fmt.Fprintln(p.w)
+	}
+	if err := export.Write(p.w, m, values); err != nil {
+		return err
+	}
+	if !p.onlyConf {
+		fmt.Fprintln(p.w)
+	}
+	return nil
+}
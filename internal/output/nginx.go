@@ -0,0 +1,42 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// PrintNginxConfig displays the nginx settings matching the FPM pool; path
+// is the parsed nginx.conf and may be empty
+func (p *Printer) PrintNginxConfig(cfg *calculator.NginxConfig, path string) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended nginx Configuration"))
+	if path != "" {
+		fmt.Fprintln(p.w, p.color(Dim, "Based on "+path))
+	}
+	fmt.Fprintln(p.w)
+
+	fmt.Fprintf(p.w, "worker_processes %s;\n", cfg.WorkerProcesses)
+	fmt.Fprintf(p.w, "worker_rlimit_nofile %d;\n", cfg.WorkerRlimitNofile)
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "events {")
+	fmt.Fprintf(p.w, "    worker_connections %d;\n", cfg.WorkerConnections)
+	fmt.Fprintln(p.w, "}")
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "http {")
+	fmt.Fprintf(p.w, "    fastcgi_buffer_size %s;\n", cfg.FastCGIBufferSize)
+	fmt.Fprintf(p.w, "    fastcgi_buffers %s;\n", cfg.FastCGIBuffers)
+	fmt.Fprintf(p.w, "    fastcgi_read_timeout %s;\n", cfg.FastCGIReadTimeout)
+	if cfg.UpstreamKeepalive > 0 {
+		fmt.Fprintln(p.w, "    fastcgi_keep_conn on;")
+		fmt.Fprintln(p.w)
+		fmt.Fprintf(p.w, "    upstream %s {\n", cfg.Upstream)
+		fmt.Fprintln(p.w, "        # keep the existing server lines")
+		fmt.Fprintf(p.w, "        keepalive %d;\n", cfg.UpstreamKeepalive)
+		fmt.Fprintln(p.w, "    }")
+	}
+	fmt.Fprintln(p.w, "}")
+	fmt.Fprintln(p.w)
+}
@@ -66,26 +66,52 @@ func (p *Printer) PrintSystemInfo(info *system.Info) {
 	p.printRow("Total Memory", fmt.Sprintf("%d MB", info.MemTotalMB))
 	p.printRow("Available Memory", fmt.Sprintf("%d MB", info.MemAvailMB))
 	p.printRow("Used Memory", fmt.Sprintf("%d MB", info.MemUsedMB))
+	p.printRow("Swap", formatSwap(info))
+	p.printRow("Overcommit", fmt.Sprintf("vm.overcommit_memory=%d", info.Overcommit))
+	p.printRow("Swappiness", fmt.Sprintf("vm.swappiness=%d", info.Swappiness))
+	if info.MemPressure != nil {
+		p.printRow("Memory Pressure", fmt.Sprintf("some %.1f%% / full %.1f%% (avg60)",
+			info.MemPressure.SomeAvg60, info.MemPressure.FullAvg60))
+	}
+	if info.Cgroup.Limited() {
+		p.printRow("Cgroup Limits", formatCgroup(info.Cgroup))
+	}
 	fmt.Fprintln(p.w)
 }
 
-// PrintPHPInfo displays detected PHP process information
-func (p *Printer) PrintPHPInfo(info *php.ProcessInfo) {
-	if p.onlyConf {
-		return
+func formatCgroup(cg *system.Cgroup) string {
+	var parts []string
+	if cg.MemoryMaxMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory.max %d MB", cg.MemoryMaxMB))
 	}
-	fmt.Fprintln(p.w, p.color(Bold, "PHP-FPM Processes"))
-	fmt.Fprintln(p.w)
+	if cg.MemoryHighMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory.high %d MB", cg.MemoryHighMB))
+	}
+	if cg.CPUQuota > 0 {
+		parts = append(parts, fmt.Sprintf("cpu %.2f cores", cg.CPUQuota))
+	}
+	return fmt.Sprintf("%s (v%d)", strings.Join(parts, ", "), cg.Version)
+}
 
-	if info.ProcessCount == 0 {
-		fmt.Fprintln(p.w, p.color(Yellow, "  No PHP-FPM processes detected"))
-		fmt.Fprintln(p.w, p.color(Dim, "  Using estimates based on php.ini memory_limit"))
-	} else {
-		p.printRow("Process Count", fmt.Sprintf("%d", info.ProcessCount))
-		p.printRow("Average Memory", fmt.Sprintf("%.1f MB", info.AvgMemoryMB))
-		p.printRow("Total Memory", fmt.Sprintf("%.1f MB", info.TotalMemMB))
+func formatSwap(info *system.Info) string {
+	if info.SwapTotalMB == 0 && !info.Zswap {
+		return "none"
 	}
-	fmt.Fprintln(p.w)
+
+	swap := fmt.Sprintf("%d MB (%d MB free)", info.SwapTotalMB, info.SwapFreeMB)
+	if info.Zram {
+		swap += ", zram"
+	}
+	if info.Zswap {
+		swap += ", zswap"
+	}
+	return swap
+}
+
+// PrintPHPInfo displays detected PHP process information
+func (p *Printer) PrintPHPInfo(info *php.ProcessInfo) {
+	p.printProcessInfo("PHP-FPM Processes", "No PHP-FPM processes detected",
+		"Using estimates based on php.ini memory_limit", "Process Count", info)
 }
 
 // PrintCalculation displays the calculation summary
@@ -97,6 +123,7 @@ func (p *Printer) PrintCalculation(cfg *calculator.Config) {
 	fmt.Fprintln(p.w)
 
 	p.printRow("Reserved Memory", fmt.Sprintf("%d MB (for OS/services)", cfg.ReservedMemoryMB))
+	p.printReservedBreakdown(cfg.ReservedBreakdown)
 	p.printRow("Available for PHP", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB))
 	p.printRow("Process Memory", fmt.Sprintf("%.1f MB", cfg.ProcessMemoryMB))
 	p.printRow("Formula", fmt.Sprintf("%d MB / %.1f MB = %d workers",
@@ -112,21 +139,10 @@ func (p *Printer) PrintConfig(cfg *calculator.Config) {
 	}
 
 	// Always print config (even in onlyConf mode)
-	fmt.Fprintf(p.w, "pm = %s\n", cfg.PM)
-	fmt.Fprintf(p.w, "pm.max_children = %d\n", cfg.MaxChildren)
-
-	if cfg.PM == calculator.PMDynamic || cfg.PM == calculator.PMOnDemand {
-		fmt.Fprintf(p.w, "pm.process_idle_timeout = %s\n", cfg.ProcessIdleTimeout)
+	for _, d := range cfg.Directives() {
+		fmt.Fprintf(p.w, "%s = %s\n", d.Name, d.Value)
 	}
 
-	if cfg.PM == calculator.PMDynamic {
-		fmt.Fprintf(p.w, "pm.start_servers = %d\n", cfg.StartServers)
-		fmt.Fprintf(p.w, "pm.min_spare_servers = %d\n", cfg.MinSpareServers)
-		fmt.Fprintf(p.w, "pm.max_spare_servers = %d\n", cfg.MaxSpareServers)
-	}
-
-	fmt.Fprintf(p.w, "pm.max_requests = %d\n", cfg.MaxRequests)
-
 	if !p.onlyConf {
 		fmt.Fprintln(p.w)
 	}
@@ -134,30 +150,12 @@ func (p *Printer) PrintConfig(cfg *calculator.Config) {
 
 // PrintWarnings displays any warnings
 func (p *Printer) PrintWarnings(cfg *calculator.Config) {
-	if p.onlyConf || len(cfg.Warnings) == 0 {
-		return
-	}
-
-	fmt.Fprintln(p.w, p.color(Bold+Yellow, "Warnings"))
-	fmt.Fprintln(p.w)
-	for _, w := range cfg.Warnings {
-		fmt.Fprintf(p.w, "  %s %s\n", p.color(Yellow, "!"), w)
-	}
-	fmt.Fprintln(p.w)
+	p.printWarnings(cfg.Warnings)
 }
 
 // PrintRecommendations displays recommendations
 func (p *Printer) PrintRecommendations(cfg *calculator.Config) {
-	if p.onlyConf || len(cfg.Recommendations) == 0 {
-		return
-	}
-
-	fmt.Fprintln(p.w, p.color(Bold+Blue, "Recommendations"))
-	fmt.Fprintln(p.w)
-	for _, r := range cfg.Recommendations {
-		fmt.Fprintf(p.w, "  %s %s\n", p.color(Cyan, "*"), r)
-	}
-	fmt.Fprintln(p.w)
+	p.printRecommendations(cfg.Recommendations)
 }
 
 // PrintUsage displays how to apply the configuration
@@ -195,6 +193,7 @@ func (p *Printer) PrintFrankenPHPCalculation(cfg *calculator.FrankenPHPConfig) {
 	fmt.Fprintln(p.w)
 
 	p.printRow("Reserved Memory", fmt.Sprintf("%d MB (for OS/Caddy)", cfg.ReservedMemoryMB))
+	p.printReservedBreakdown(cfg.ReservedBreakdown)
 	p.printRow("Available for PHP", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB))
 	p.printRow("Thread Memory", fmt.Sprintf("%.1f MB", cfg.ThreadMemoryMB))
 	p.printRow("Formula", fmt.Sprintf("%d MB / %.1f MB = %d threads",
@@ -212,57 +211,69 @@ func (p *Printer) PrintFrankenPHPConfig(cfg *calculator.FrankenPHPConfig, worker
 	// Print Caddyfile format
 	fmt.Fprintln(p.w, "{")
 	fmt.Fprintln(p.w, "    frankenphp {")
-	fmt.Fprintf(p.w, "        num_threads %d\n", cfg.NumThreads)
+	for _, line := range frankenphpOptions(cfg, workerMode, "/path/to/your/public/index.php") {
+		fmt.Fprintln(p.w, "        "+line)
+	}
+	fmt.Fprintln(p.w, "    }")
+	fmt.Fprintln(p.w, "}")
 
-	if cfg.MaxThreads > cfg.NumThreads {
-		fmt.Fprintf(p.w, "        max_threads %d\n", cfg.MaxThreads)
+	if p.onlyConf {
+		return
 	}
+	fmt.Fprintln(p.w)
 
-	if cfg.MaxWaitTime != "" {
-		fmt.Fprintf(p.w, "        max_wait_time %s\n", cfg.MaxWaitTime)
+	// Frameworks also read these from .env when started through their CLI
+	if len(cfg.Env) > 0 {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Environment ("+string(cfg.Framework)+")"))
+		fmt.Fprintln(p.w)
+		for _, env := range cfg.Env {
+			fmt.Fprintf(p.w, "%s=%s\n", env.Name, env.Value)
+		}
+		fmt.Fprintln(p.w)
 	}
+}
+
+// frankenphpOptions returns the lines inside the frankenphp global option,
+// workers without a script use defaultFile
+func frankenphpOptions(cfg *calculator.FrankenPHPConfig, workerMode bool, defaultFile string) []string {
+	lines := []string{fmt.Sprintf("num_threads %d", cfg.NumThreads)}
 
-	if workerMode && cfg.WorkerNum > 0 {
-		fmt.Fprintln(p.w, "        worker {")
-		fmt.Fprintln(p.w, "            file /path/to/your/public/index.php")
-		fmt.Fprintf(p.w, "            num %d\n", cfg.WorkerNum)
-		fmt.Fprintln(p.w, "        }")
+	if cfg.MaxThreads > cfg.NumThreads {
+		lines = append(lines, fmt.Sprintf("max_threads %d", cfg.MaxThreads))
 	}
 
-	fmt.Fprintln(p.w, "    }")
-	fmt.Fprintln(p.w, "}")
+	if cfg.MaxWaitTime != "" {
+		lines = append(lines, fmt.Sprintf("max_wait_time %s", cfg.MaxWaitTime))
+	}
 
-	if !p.onlyConf {
-		fmt.Fprintln(p.w)
+	if !workerMode {
+		return lines
 	}
+	for _, worker := range cfg.Workers {
+		file := worker.File
+		if file == "" {
+			file = defaultFile
+		}
+		lines = append(lines, "worker {", "    file "+file, fmt.Sprintf("    num %d", worker.Num))
+		if worker.MaxThreads > worker.Num {
+			lines = append(lines, fmt.Sprintf("    max_threads %d", worker.MaxThreads))
+		}
+		for _, env := range worker.Env {
+			lines = append(lines, fmt.Sprintf("    env %s %s", env.Name, env.Value))
+		}
+		lines = append(lines, "}")
+	}
+	return lines
 }
 
 // PrintFrankenPHPWarnings displays FrankenPHP warnings
 func (p *Printer) PrintFrankenPHPWarnings(cfg *calculator.FrankenPHPConfig) {
-	if p.onlyConf || len(cfg.Warnings) == 0 {
-		return
-	}
-
-	fmt.Fprintln(p.w, p.color(Bold+Yellow, "Warnings"))
-	fmt.Fprintln(p.w)
-	for _, w := range cfg.Warnings {
-		fmt.Fprintf(p.w, "  %s %s\n", p.color(Yellow, "!"), w)
-	}
-	fmt.Fprintln(p.w)
+	p.printWarnings(cfg.Warnings)
 }
 
 // PrintFrankenPHPRecommendations displays FrankenPHP recommendations
 func (p *Printer) PrintFrankenPHPRecommendations(cfg *calculator.FrankenPHPConfig) {
-	if p.onlyConf || len(cfg.Recommendations) == 0 {
-		return
-	}
-
-	fmt.Fprintln(p.w, p.color(Bold+Blue, "Recommendations"))
-	fmt.Fprintln(p.w)
-	for _, r := range cfg.Recommendations {
-		fmt.Fprintf(p.w, "  %s %s\n", p.color(Cyan, "*"), r)
-	}
-	fmt.Fprintln(p.w)
+	p.printRecommendations(cfg.Recommendations)
 }
 
 // PrintFrankenPHPUsage displays how to apply FrankenPHP configuration
@@ -283,6 +294,86 @@ func (p *Printer) PrintFrankenPHPUsage() {
 	fmt.Fprintln(p.w)
 }
 
+func (p *Printer) printProcessInfo(title, missing, fallback, countLabel string, info *php.ProcessInfo) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, title))
+	fmt.Fprintln(p.w)
+
+	if info.ProcessCount == 0 {
+		fmt.Fprintln(p.w, p.color(Yellow, "  "+missing))
+		fmt.Fprintln(p.w, p.color(Dim, "  "+fallback))
+	} else {
+		p.printRow(countLabel, fmt.Sprintf("%d", info.ProcessCount))
+		p.printRow("Average Memory", fmt.Sprintf("%.1f MB", info.AvgMemoryMB))
+		p.printRow("Total Memory", fmt.Sprintf("%.1f MB", info.TotalMemMB))
+	}
+	fmt.Fprintln(p.w)
+}
+
+func (p *Printer) printWarnings(warnings []string) {
+	if p.onlyConf || len(warnings) == 0 {
+		return
+	}
+
+	fmt.Fprintln(p.w, p.color(Bold+Yellow, "Warnings"))
+	fmt.Fprintln(p.w)
+	for _, w := range warnings {
+		fmt.Fprintf(p.w, "  %s %s\n", p.color(Yellow, "!"), w)
+	}
+	fmt.Fprintln(p.w)
+}
+
+func (p *Printer) printRecommendations(recommendations []string) {
+	if p.onlyConf || len(recommendations) == 0 {
+		return
+	}
+
+	fmt.Fprintln(p.w, p.color(Bold+Blue, "Recommendations"))
+	fmt.Fprintln(p.w)
+	for _, r := range recommendations {
+		fmt.Fprintf(p.w, "  %s %s\n", p.color(Cyan, "*"), r)
+	}
+	fmt.Fprintln(p.w)
+}
+
+func (p *Printer) printReservedBreakdown(items []calculator.ReservedItem) {
+	if len(items) < 2 {
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintf(p.w, "    %s %-16s %6d MB %s\n", p.color(Dim, "-"), item.Label, item.MemoryMB,
+			p.color(Dim, "("+item.Source+")"))
+	}
+}
+
+// printTable prints rows with aligned columns, the first row as header;
+// rowColor optionally colors data rows by index (starting at 0)
+func (p *Printer) printTable(rows [][]string, rowColor func(int) string) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	for n, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		line := strings.TrimRight("  "+strings.Join(cells, "  "), " ")
+		switch {
+		case n == 0:
+			line = p.color(Bold, line)
+		case rowColor != nil:
+			line = p.color(rowColor(n-1), line)
+		}
+		fmt.Fprintln(p.w, line)
+	}
+}
+
 func (p *Printer) printRow(label, value string) {
 	fmt.Fprintf(p.w, "  %-20s %s\n", p.color(Dim, label), value)
 }
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+// PrintRoadRunnerHeader prints the RoadRunner header
+func (p *Printer) PrintRoadRunnerHeader() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "RoadRunner Optimizer"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+}
+
+// PrintRoadRunnerInfo displays detected RoadRunner workers
+func (p *Printer) PrintRoadRunnerInfo(info *php.ProcessInfo) {
+	p.printProcessInfo("RoadRunner Workers", "No RoadRunner workers detected",
+		"Using a 50MB worker estimate", "Worker Count", info)
+}
+
+// PrintRoadRunnerCalculation displays the RoadRunner calculation summary
+func (p *Printer) PrintRoadRunnerCalculation(cfg *calculator.RoadRunnerConfig) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "Calculation"))
+	fmt.Fprintln(p.w)
+
+	p.printRow("Reserved Memory", fmt.Sprintf("%d MB (for OS/RoadRunner)", cfg.ReservedMemoryMB))
+	p.printReservedBreakdown(cfg.ReservedBreakdown)
+	p.printRow("Available for PHP", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB))
+	p.printRow("Worker Memory", fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB))
+	p.printRow("Formula", fmt.Sprintf("%d workers x %.1f MB = %.0f MB",
+		cfg.NumWorkers, cfg.WorkerMemoryMB, float64(cfg.NumWorkers)*cfg.WorkerMemoryMB))
+	fmt.Fprintln(p.w)
+}
+
+// PrintRoadRunnerConfig displays the recommended .rr.yaml pool section
+func (p *Printer) PrintRoadRunnerConfig(cfg *calculator.RoadRunnerConfig) {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended Configuration"))
+		fmt.Fprintln(p.w)
+	}
+
+	fmt.Fprintln(p.w, "http:")
+	fmt.Fprintln(p.w, "  pool:")
+	fmt.Fprintf(p.w, "    num_workers: %d\n", cfg.NumWorkers)
+	fmt.Fprintf(p.w, "    max_jobs: %d\n", cfg.MaxJobs)
+	fmt.Fprintln(p.w, "    supervisor:")
+	fmt.Fprintf(p.w, "      max_worker_memory: %d\n", cfg.MaxWorkerMemoryMB)
+
+	if !p.onlyConf {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// PrintRoadRunnerWarnings displays RoadRunner warnings
+func (p *Printer) PrintRoadRunnerWarnings(cfg *calculator.RoadRunnerConfig) {
+	p.printWarnings(cfg.Warnings)
+}
+
+// PrintRoadRunnerRecommendations displays RoadRunner recommendations
+func (p *Printer) PrintRoadRunnerRecommendations(cfg *calculator.RoadRunnerConfig) {
+	p.printRecommendations(cfg.Recommendations)
+}
+
+// PrintRoadRunnerUsage displays how to apply the RoadRunner configuration
+func (p *Printer) PrintRoadRunnerUsage() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  1. Merge the pool settings into .rr.yaml")
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  2. Reset the workers:")
+	fmt.Fprintln(p.w, p.color(Dim, "     rr reset"))
+	fmt.Fprintln(p.w)
+}
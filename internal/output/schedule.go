@@ -0,0 +1,181 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// Swap copies a window's pool config over the deployed one and reloads
+type Swap struct {
+	Window calculator.Window
+	Source string // Pool config for the window
+	Target string // Deployed pool config
+	Reload string // Command that reloads PHP-FPM
+}
+
+// unitName is the systemd unit (without suffix) that activates a window
+func (s Swap) unitName() string {
+	return "php-tuner-schedule-" + s.Window.String()
+}
+
+// PrintSchedule tabulates the PHP-FPM config of each window
+func (p *Printer) PrintSchedule(s *calculator.Schedule) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "Windows"))
+	fmt.Fprintln(p.w)
+
+	table := [][]string{{"window", "traffic", "pm", "max_children", "start", "min_spare", "max_spare",
+		"idle_timeout", "max_requests"}}
+	for i, w := range s.Windows {
+		c := s.Configs[i]
+		table = append(table, []string{w.String(), w.Traffic(), string(c.PM), fmt.Sprint(c.MaxChildren),
+			fmt.Sprint(c.StartServers), fmt.Sprint(c.MinSpareServers), fmt.Sprint(c.MaxSpareServers),
+			c.ProcessIdleTimeout, fmt.Sprint(c.MaxRequests)})
+	}
+	p.printTable(table, nil)
+	fmt.Fprintln(p.w)
+}
+
+// PrintFrankenPHPSchedule tabulates the FrankenPHP config of each window
+func (p *Printer) PrintFrankenPHPSchedule(s *calculator.FrankenPHPSchedule) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "Windows"))
+	fmt.Fprintln(p.w)
+
+	table := [][]string{{"window", "traffic", "num_threads", "max_threads", "worker_num", "max_wait_time"}}
+	for i, w := range s.Windows {
+		c := s.Configs[i]
+		wait := c.MaxWaitTime
+		if wait == "" {
+			wait = "-"
+		}
+		table = append(table, []string{w.String(), w.Traffic(), fmt.Sprint(c.NumThreads),
+			fmt.Sprint(c.MaxThreads), fmt.Sprint(c.WorkerNum), wait})
+	}
+	p.printTable(table, nil)
+	fmt.Fprintln(p.w)
+}
+
+// PrintScheduleHeader prints the schedule header
+func (p *Printer) PrintScheduleHeader() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "Traffic Schedule"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+}
+
+// hourPattern matches the window's hours as date +%H prints them
+func hourPattern(w calculator.Window) string {
+	hours := w.Hours()
+	patterns := make([]string, len(hours))
+	for i, h := range hours {
+		patterns[i] = fmt.Sprintf("%02d", h)
+	}
+	return strings.Join(patterns, "|")
+}
+
+// applyCurrent is a shell command that swaps in the config of the window
+// the current hour falls into; percent is how the unit or file spells %
+func applyCurrent(swaps []Swap, percent string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `case "$(date +%sH)" in`, percent)
+	for _, s := range swaps {
+		fmt.Fprintf(&b, " %s) cp %s %s && %s ;;", hourPattern(s.Window), s.Source, s.Target, s.Reload)
+	}
+	b.WriteString(" esac")
+	return b.String()
+}
+
+// PrintCron prints an /etc/cron.d file that swaps pool configs at the start
+// of each window and applies the current window after a reboot
+func (p *Printer) PrintCron(swaps []Swap) {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Cron Schedule"))
+		fmt.Fprintln(p.w)
+	}
+
+	fmt.Fprintln(p.w, "# /etc/cron.d/php-tuner-schedule")
+	fmt.Fprintln(p.w, "SHELL=/bin/sh")
+	fmt.Fprintln(p.w, "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin")
+	for _, s := range swaps {
+		fmt.Fprintf(p.w, "0 %d * * * root cp %s %s && %s\n", s.Window.Start, s.Source, s.Target, s.Reload)
+	}
+	// cron runs nothing it missed while the machine was down
+	fmt.Fprintf(p.w, "@reboot root %s\n", applyCurrent(swaps, `\%`))
+
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  Save the file, then apply the current window once, cron only switches")
+	fmt.Fprintln(p.w, "  when the next window starts:")
+	fmt.Fprintln(p.w, p.color(Dim, "     sudo sh -c '"+applyCurrent(swaps, "%")+"'"))
+	fmt.Fprintln(p.w)
+}
+
+// PrintSystemdTimers prints a oneshot service and timer per window that
+// swap pool configs at the start of the window. The timers are persistent,
+// so a start missed while the machine was down runs at boot; a service
+// only applies its config during its own window.
+func (p *Printer) PrintSystemdTimers(swaps []Swap) {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "systemd Timers"))
+		fmt.Fprintln(p.w)
+	}
+
+	timers := make([]string, 0, len(swaps))
+	services := make([]string, 0, len(swaps))
+	for _, s := range swaps {
+		name := s.unitName()
+		timers = append(timers, name+".timer")
+		services = append(services, name+".service")
+
+		fmt.Fprintf(p.w, "# /etc/systemd/system/%s.service\n", name)
+		fmt.Fprintln(p.w, "[Unit]")
+		fmt.Fprintf(p.w, "Description=Apply the %s PHP-FPM pool config\n", s.Window)
+		fmt.Fprintln(p.w)
+		fmt.Fprintln(p.w, "[Service]")
+		fmt.Fprintln(p.w, "Type=oneshot")
+		// Skipped outside the window, e.g. for a missed start caught up at boot
+		fmt.Fprintf(p.w, "ExecCondition=/bin/sh -c 'case \"$(date +%%%%H)\" in %s) exit 0 ;; esac; exit 1'\n",
+			hourPattern(s.Window))
+		fmt.Fprintf(p.w, "ExecStart=cp %s %s\n", s.Source, s.Target)
+		fmt.Fprintf(p.w, "ExecStart=%s\n", s.Reload)
+		fmt.Fprintln(p.w)
+
+		fmt.Fprintf(p.w, "# /etc/systemd/system/%s.timer\n", name)
+		fmt.Fprintln(p.w, "[Unit]")
+		fmt.Fprintf(p.w, "Description=Switch PHP-FPM to the %s pool config\n", s.Window)
+		fmt.Fprintln(p.w)
+		fmt.Fprintln(p.w, "[Timer]")
+		fmt.Fprintf(p.w, "OnCalendar=*-*-* %02d:00:00\n", s.Window.Start)
+		fmt.Fprintln(p.w, "Persistent=true")
+		fmt.Fprintln(p.w)
+		fmt.Fprintln(p.w, "[Install]")
+		fmt.Fprintln(p.w, "WantedBy=timers.target")
+		fmt.Fprintln(p.w)
+	}
+
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  Save the units, enable the timers and apply the current window once;")
+	fmt.Fprintln(p.w, "  only the service of the current window passes its condition:")
+	fmt.Fprintln(p.w, p.color(Dim, "     sudo systemctl daemon-reload"))
+	fmt.Fprintln(p.w, p.color(Dim, "     sudo systemctl enable --now "+strings.Join(timers, " ")))
+	fmt.Fprintln(p.w, p.color(Dim, "     sudo systemctl start "+strings.Join(services, " ")))
+	fmt.Fprintln(p.w)
+}
@@ -0,0 +1,86 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+)
+
+// PrintSwooleHeader prints the Swoole header
+func (p *Printer) PrintSwooleHeader() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, p.color(Bold+Cyan, "Swoole / OpenSwoole Optimizer"))
+	fmt.Fprintln(p.w, p.color(Dim, strings.Repeat("─", 40)))
+	fmt.Fprintln(p.w)
+}
+
+// PrintSwooleInfo displays detected Swoole worker and task processes
+func (p *Printer) PrintSwooleInfo(info *php.ProcessInfo) {
+	p.printProcessInfo("Swoole Workers", "No Swoole workers detected",
+		"Using a 50MB worker estimate", "Worker Count", info)
+}
+
+// PrintSwooleCalculation displays the Swoole calculation summary
+func (p *Printer) PrintSwooleCalculation(cfg *calculator.SwooleConfig) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "Calculation"))
+	fmt.Fprintln(p.w)
+
+	processes := cfg.WorkerNum + cfg.TaskWorkerNum
+	p.printRow("Reserved Memory", fmt.Sprintf("%d MB (for OS/Swoole)", cfg.ReservedMemoryMB))
+	p.printReservedBreakdown(cfg.ReservedBreakdown)
+	p.printRow("Available for PHP", fmt.Sprintf("%d MB", cfg.AvailableMemoryMB))
+	p.printRow("Worker Memory", fmt.Sprintf("%.1f MB", cfg.WorkerMemoryMB))
+	p.printRow("Formula", fmt.Sprintf("%d processes x %.1f MB = %.0f MB",
+		processes, cfg.WorkerMemoryMB, float64(processes)*cfg.WorkerMemoryMB))
+	fmt.Fprintln(p.w)
+}
+
+// PrintSwooleConfig displays the recommended server settings as PHP
+func (p *Printer) PrintSwooleConfig(cfg *calculator.SwooleConfig) {
+	if !p.onlyConf {
+		fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended Configuration"))
+		fmt.Fprintln(p.w)
+	}
+
+	fmt.Fprintln(p.w, "$server->set([")
+	for _, d := range cfg.Directives() {
+		fmt.Fprintf(p.w, "    '%s' => %s,\n", d.Name, d.Value)
+	}
+	fmt.Fprintln(p.w, "]);")
+
+	if !p.onlyConf {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// PrintSwooleWarnings displays Swoole warnings
+func (p *Printer) PrintSwooleWarnings(cfg *calculator.SwooleConfig) {
+	p.printWarnings(cfg.Warnings)
+}
+
+// PrintSwooleRecommendations displays Swoole recommendations
+func (p *Printer) PrintSwooleRecommendations(cfg *calculator.SwooleConfig) {
+	p.printRecommendations(cfg.Recommendations)
+}
+
+// PrintSwooleUsage displays how to apply the Swoole configuration
+func (p *Printer) PrintSwooleUsage() {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold, "How to Apply"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  1. Pass the settings to $server->set(), or with Laravel Octane:")
+	fmt.Fprintln(p.w, p.color(Dim, "     config/octane.php: 'swoole' => ['options' => [...]]"))
+	fmt.Fprintln(p.w)
+	fmt.Fprintln(p.w, "  2. Restart the server (worker_num changes need a full restart)")
+	fmt.Fprintln(p.w)
+}
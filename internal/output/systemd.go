@@ -0,0 +1,20 @@
+package output
+
+import (
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/systemd"
+)
+
+// PrintSystemdDropIn displays the drop-in that enforces the budget for unit
+func (p *Printer) PrintSystemdDropIn(cfg *calculator.SystemdConfig, unit string) {
+	if p.onlyConf {
+		return
+	}
+	fmt.Fprintln(p.w, p.color(Bold+Green, "Recommended systemd Drop-in"))
+	fmt.Fprintln(p.w, p.color(Dim, systemd.DropInPath(unit)))
+	fmt.Fprintln(p.w)
+	fmt.Fprint(p.w, systemd.Render(cfg.Directives()))
+	fmt.Fprintln(p.w)
+}
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// Result is the model a user template renders: .System, .PHP, .Services
+// and .MemoryLimitMB of the input, the runtime's .Config with its
+// .Directives, the optional .Nginx and .Systemd configs, and the
+// .Warnings, .Recommendations and .Trace
+type Result struct {
+	calculator.Input
+	Runtime         string
+	Config          any
+	Directives      []calculator.Directive
+	Nginx           *calculator.NginxConfig   // Only with --nginx or --nginx-conf
+	Systemd         *calculator.SystemdConfig // Only with --systemd
+	Warnings        []string
+	Recommendations []string
+	Trace           calculator.Trace
+}
+
+// LoadTemplate parses a text/template file with the helper functions
+func LoadTemplate(path string) (*template.Template, error) {
+	return template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+}
+
+// PrintTemplate renders the result through a user template. Nothing is
+// written when rendering fails halfway.
+func (p *Printer) PrintTemplate(t *template.Template, r Result) error {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, r); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(p.w)
+	return err
+}
+
+var templateFuncs = template.FuncMap{
+	// Sizes: {{ mb .System.MemTotalMB }} -> "7.8 GB"
+	"bytes": func(v any) (string, error) { return formatSize(v, 1) },
+	"kb":    func(v any) (string, error) { return formatSize(v, 1<<10) },
+	"mb":    func(v any) (string, error) { return formatSize(v, 1<<20) },
+
+	// Rounding: {{ round .Config.ProcessMemoryMB 1 }}
+	"round": func(v any, digits int) (float64, error) {
+		f, err := toFloat(v)
+		scale := math.Pow(10, float64(digits))
+		return math.Round(f*scale) / scale, err
+	},
+	"ceil": func(v any) (int, error) {
+		f, err := toFloat(v)
+		return int(math.Ceil(f)), err
+	},
+	"floor": func(v any) (int, error) {
+		f, err := toFloat(v)
+		return int(math.Floor(f)), err
+	},
+
+	// Durations: {{ duration "90s" }} -> 1m30s, {{ seconds .Config.MaxWaitTime }} -> 10
+	"duration": toDuration,
+	"seconds": func(v any) (float64, error) {
+		d, err := toDuration(v)
+		return d.Seconds(), err
+	},
+
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// formatSize formats v units of unit bytes with binary prefixes
+func formatSize(v any, unit float64) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	size := f * unit
+
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	i := 0
+	for math.Abs(size) >= 1024 && i < len(units)-1 {
+		size /= 1024
+		i++
+	}
+	if i == 0 || size == math.Trunc(size) {
+		return fmt.Sprintf("%.0f %s", size, units[i]), nil
+	}
+	return fmt.Sprintf("%.1f %s", size, units[i]), nil
+}
+
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
+
+// toDuration accepts a time.Duration, seconds, a config duration like "30"
+// or "2d", or a Go duration string. An empty string is zero, like an unset
+// timeout.
+func toDuration(v any) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		if d == "" {
+			return 0, nil
+		}
+		if parsed, err := calculator.ParseDuration(d); err == nil {
+			return parsed, nil
+		}
+		return time.ParseDuration(d)
+	default:
+		f, err := toFloat(v)
+		return time.Duration(f * float64(time.Second)), err
+	}
+}
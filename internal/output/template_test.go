@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		data any
+		want string
+	}{
+		{`{{ bytes 512 }}`, nil, "512 B"},
+		{`{{ kb 2048 }}`, nil, "2 MB"},
+		{`{{ mb . }}`, 8000, "7.8 GB"},
+		{`{{ mb "1024" }}`, nil, "1 GB"},
+		{`{{ round . 1 }}`, 42.345, "42.3"},
+		{`{{ ceil . }}`, 2.1, "3"},
+		{`{{ floor . }}`, 2.9, "2"},
+		{`{{ seconds "30" }}`, nil, "30"},
+		{`{{ seconds "5m" }}`, nil, "300"},
+		{`{{ seconds "2d" }}`, nil, "172800"},
+		{`{{ seconds "1m30s" }}`, nil, "90"},
+		{`{{ seconds "" }}`, nil, "0"},
+		{`{{ seconds . }}`, 2 * time.Minute, "120"},
+		{`{{ duration 90 }}`, nil, "1m30s"},
+		{`{{ duration "1h" }}`, nil, "1h0m0s"},
+		{`{{ join . "," }}`, []string{"a", "b"}, "a,b"},
+		{`{{ upper "pm" }} {{ lower "PM" }}`, nil, "PM pm"},
+	}
+	for _, tt := range tests {
+		tmpl := template.Must(template.New("").Funcs(templateFuncs).Parse(tt.tmpl))
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, tt.data); err != nil {
+			t.Errorf("%s: %v", tt.tmpl, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{`{{ seconds "soon" }}`, `{{ mb "lots" }}`, `{{ round true 1 }}`} {
+		tmpl := template.Must(template.New("").Funcs(templateFuncs).Parse(bad))
+		if err := tmpl.Execute(&bytes.Buffer{}, nil); err == nil {
+			t.Errorf("%s rendered without an error", bad)
+		}
+	}
+}
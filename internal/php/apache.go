@@ -0,0 +1,31 @@
+package php
+
+import "context"
+
+// DetectApacheProcesses finds the apache2/httpd children serving mod_php
+// requests; the parent process is excluded since it never runs PHP
+func DetectApacheProcesses(ctx context.Context) (*ProcessInfo, error) {
+	all, err := DetectProcessesMatching(ctx, "^(apache2|httpd)$")
+	if err != nil || all.ProcessCount == 0 {
+		return all, err
+	}
+
+	pids := map[int]bool{}
+	for _, proc := range all.Processes {
+		pids[proc.PID] = true
+	}
+
+	var children []Process
+	for _, proc := range all.Processes {
+		if pids[getParentPID(proc.PID)] {
+			children = append(children, proc)
+		}
+	}
+
+	// A single process (e.g. apache2 -X) serves requests itself
+	if len(children) == 0 {
+		return all, nil
+	}
+
+	return summarize(children), nil
+}
@@ -2,59 +2,82 @@ package php
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
 // ProcessInfo holds information about PHP-FPM processes
 type ProcessInfo struct {
-	ProcessCount int
-	AvgMemoryMB  float64
-	TotalMemMB   float64
-	Processes    []Process
+	ProcessCount int       `json:"process_count"`
+	AvgMemoryMB  float64   `json:"avg_memory_mb"`
+	TotalMemMB   float64   `json:"total_mem_mb"`
+	Processes    []Process `json:"processes"`
 }
 
 // Process represents a single PHP-FPM process
 type Process struct {
-	PID      int
-	MemoryKB int64
-	Command  string
+	PID         int    `json:"pid"`
+	MemoryKB    int64  `json:"memory_kb"`
+	Command     string `json:"command"`
+	OOMScoreAdj int    `json:"oom_score_adj"`
+	PSSKB       int64  `json:"pss_kb,omitempty"` // Proportional set size, shared pages split between processes
+	Pool        string `json:"pool,omitempty"`   // PHP-FPM pool name, empty for the master process
 }
 
 // DetectProcesses finds and analyzes PHP-FPM processes
-func DetectProcesses() (*ProcessInfo, error) {
+func DetectProcesses(ctx context.Context) (*ProcessInfo, error) {
+	return DetectProcessesMatching(ctx, "php-fpm|php[0-9]")
+}
+
+// DetectProcessesMatching analyzes processes whose command name matches the
+// regular expression pattern; the scan stops once ctx is done
+func DetectProcessesMatching(ctx context.Context, pattern string) (*ProcessInfo, error) {
 	info := &ProcessInfo{}
 
-	out, err := exec.Command("sh", "-c", "ps -eo pid,comm | grep -E 'php-fpm|php[0-9]'").Output()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return info, fmt.Errorf("invalid process pattern %q: %w", pattern, err)
+	}
+
+	entries, err := os.ReadDir("/proc")
 	if err != nil {
 		return info, nil
 	}
 
-	scanner := bufio.NewScanner(strings.NewReader(string(out)))
-	for scanner.Scan() {
-		fields := strings.Fields(scanner.Text())
-		if len(fields) < 2 {
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return info, err
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
 			continue
 		}
 
-		pid, err := strconv.Atoi(fields[0])
+		// Processes may exit between listing and reading
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "comm"))
 		if err != nil {
 			continue
 		}
-
-		if strings.Contains(fields[1], "grep") {
+		comm := strings.TrimSpace(string(data))
+		if !re.MatchString(comm) {
 			continue
 		}
 
 		memKB := getProcessMemory(pid)
 		if memKB > 0 {
 			info.Processes = append(info.Processes, Process{
-				PID:      pid,
-				MemoryKB: memKB,
-				Command:  fields[1],
+				PID:         pid,
+				MemoryKB:    memKB,
+				Command:     comm,
+				OOMScoreAdj: getOOMScoreAdj(pid),
+				PSSKB:       getProcessPSS(pid),
+				Pool:        getPoolName(pid),
 			})
 			info.TotalMemMB += float64(memKB) / 1024
 		}
@@ -91,17 +114,62 @@ func getProcessMemory(pid int) int64 {
 	return 0
 }
 
+func getProcessPSS(pid int) int64 {
+	file, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "smaps_rollup"))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "Pss:" {
+			val, _ := strconv.ParseInt(fields[1], 10, 64)
+			return val
+		}
+	}
+
+	return 0
+}
+
+// getPoolName extracts the pool from the FPM process title "php-fpm: pool www"
+func getPoolName(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return ""
+	}
+
+	title := strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+	if _, pool, ok := strings.Cut(title, ": pool "); ok {
+		return strings.TrimSpace(pool)
+	}
+	return ""
+}
+
+func getOOMScoreAdj(pid int) int {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "oom_score_adj"))
+	if err != nil {
+		return 0
+	}
+
+	val, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return val
+}
+
 // GetPHPMemoryLimit attempts to read the PHP memory_limit setting
-func GetPHPMemoryLimit() (int, error) {
-	out, err := exec.Command("php", "-r", "echo ini_get('memory_limit');").Output()
+func GetPHPMemoryLimit(ctx context.Context) (int, error) {
+	out, err := exec.CommandContext(ctx, "php", "-r", "echo ini_get('memory_limit');").Output()
 	if err != nil {
 		return 0, err
 	}
 
-	return parseMemoryLimit(strings.TrimSpace(string(out)))
+	return ParseMemoryLimit(strings.TrimSpace(string(out)))
 }
 
-func parseMemoryLimit(limit string) (int, error) {
+// ParseMemoryLimit converts a php.ini size like 128M or 1G to megabytes,
+// returning -1 for unlimited
+func ParseMemoryLimit(limit string) (int, error) {
 	limit = strings.ToUpper(strings.TrimSpace(limit))
 
 	if limit == "-1" {
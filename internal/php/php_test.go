@@ -0,0 +1,19 @@
+package php
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDetectProcessesMatchingCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := DetectProcessesMatching(ctx, "."); !errors.Is(err, context.Canceled) {
+		t.Errorf("DetectProcessesMatching with a cancelled context = %v, want context.Canceled", err)
+	}
+	if _, err := GetPHPMemoryLimit(ctx); err == nil {
+		t.Error("GetPHPMemoryLimit ran with a cancelled context")
+	}
+}
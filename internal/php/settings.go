@@ -0,0 +1,40 @@
+package php
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+)
+
+// SettingNames are the php.ini settings relevant for tuning
+var SettingNames = []string{
+	"memory_limit",
+	"max_execution_time",
+	"opcache.enable",
+	"opcache.memory_consumption",
+	"opcache.interned_strings_buffer",
+	"realpath_cache_size",
+	"post_max_size",
+	"upload_max_filesize",
+}
+
+// GetSettings reads the tuning relevant php.ini settings from the php CLI
+func GetSettings(ctx context.Context) (map[string]string, error) {
+	names, err := json.Marshal(SettingNames)
+	if err != nil {
+		return nil, err
+	}
+
+	script := `$s = []; foreach (json_decode($argv[1]) as $n) { $s[$n] = (string) ini_get($n); } echo json_encode($s);`
+	out, err := exec.CommandContext(ctx, "php", "-r", script, "--", string(names)).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]string{}
+	if err := json.Unmarshal(out, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,56 @@
+package php
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Status holds the PHP-FPM status page metrics (pm.status_path)
+type Status struct {
+	Pool               string `json:"pool"`
+	ProcessManager     string `json:"process manager"`
+	StartSince         int64  `json:"start since"`
+	AcceptedConn       int64  `json:"accepted conn"`
+	ListenQueue        int    `json:"listen queue"`
+	MaxListenQueue     int    `json:"max listen queue"`
+	ListenQueueLen     int    `json:"listen queue len"`
+	IdleProcesses      int    `json:"idle processes"`
+	ActiveProcesses    int    `json:"active processes"`
+	TotalProcesses     int    `json:"total processes"`
+	MaxActiveProcesses int    `json:"max active processes"`
+	MaxChildrenReached int64  `json:"max children reached"`
+	SlowRequests       int64  `json:"slow requests"`
+}
+
+// FetchStatus reads the PHP-FPM status page in JSON format over HTTP
+func FetchStatus(statusURL string) (*Status, error) {
+	u, err := url.Parse(statusURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status URL: %w", err)
+	}
+
+	query := u.Query()
+	query.Set("json", "")
+	u.RawQuery = query.Encode()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status page returned %s", resp.Status)
+	}
+
+	status := &Status{}
+	if err := json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, fmt.Errorf("failed to parse status page: %w", err)
+	}
+
+	return status, nil
+}
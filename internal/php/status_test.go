@@ -0,0 +1,49 @@
+package php
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !r.URL.Query().Has("json") {
+			t.Errorf("query %q lacks json", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("full") != "1" {
+			t.Errorf("query %q lost full=1", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"pool":"www","process manager":"dynamic","active processes":3,"total processes":5,"max children reached":2}`))
+	}))
+	defer srv.Close()
+
+	status, err := FetchStatus(srv.URL + "/status?full=1")
+	if err != nil {
+		t.Fatalf("FetchStatus: %v", err)
+	}
+	if status.Pool != "www" || status.ProcessManager != "dynamic" {
+		t.Errorf("pool %q, pm %q", status.Pool, status.ProcessManager)
+	}
+	if status.ActiveProcesses != 3 || status.TotalProcesses != 5 || status.MaxChildrenReached != 2 {
+		t.Errorf("unexpected counters %+v", status)
+	}
+}
+
+func TestFetchStatusErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.Write([]byte("pool: www"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/missing", "/broken"} {
+		if _, err := FetchStatus(srv.URL + path); err == nil {
+			t.Errorf("%s: expected an error", path)
+		}
+	}
+}
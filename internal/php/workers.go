@@ -0,0 +1,95 @@
+package php
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cliPattern matches the PHP CLI binary (php, php8.3) but not php-fpm
+const cliPattern = "^php[0-9.]*$"
+
+// DetectRoadRunnerWorkers finds the PHP worker processes spawned by rr
+func DetectRoadRunnerWorkers(ctx context.Context) (*ProcessInfo, error) {
+	all, err := DetectProcessesMatching(ctx, cliPattern)
+	if err != nil {
+		return all, err
+	}
+
+	var workers []Process
+	for _, proc := range all.Processes {
+		if getComm(getParentPID(proc.PID)) == "rr" {
+			workers = append(workers, proc)
+		}
+	}
+
+	return summarize(workers), nil
+}
+
+// DetectSwooleWorkers finds Swoole/OpenSwoole worker and task processes:
+// PHP processes forked by another PHP process (the manager) that have no
+// children of their own, which excludes the master and manager
+func DetectSwooleWorkers(ctx context.Context) (*ProcessInfo, error) {
+	all, err := DetectProcessesMatching(ctx, cliPattern)
+	if err != nil {
+		return all, err
+	}
+
+	parents := map[int]int{}
+	for _, proc := range all.Processes {
+		parents[proc.PID] = getParentPID(proc.PID)
+	}
+
+	hasChildren := map[int]bool{}
+	for _, ppid := range parents {
+		hasChildren[ppid] = true
+	}
+
+	var workers []Process
+	for _, proc := range all.Processes {
+		if _, forked := parents[parents[proc.PID]]; forked && !hasChildren[proc.PID] {
+			workers = append(workers, proc)
+		}
+	}
+
+	return summarize(workers), nil
+}
+
+// summarize computes totals and the average for a set of processes
+func summarize(procs []Process) *ProcessInfo {
+	info := &ProcessInfo{Processes: procs, ProcessCount: len(procs)}
+	for _, proc := range procs {
+		info.TotalMemMB += float64(proc.MemoryKB) / 1024
+	}
+	if info.ProcessCount > 0 {
+		info.AvgMemoryMB = info.TotalMemMB / float64(info.ProcessCount)
+	}
+	return info
+}
+
+func getParentPID(pid int) int {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0
+	}
+
+	// Format: pid (comm) state ppid ...; comm may contain spaces
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+	if len(fields) < 2 {
+		return 0
+	}
+
+	ppid, _ := strconv.Atoi(fields[1])
+	return ppid
+}
+
+func getComm(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "comm"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
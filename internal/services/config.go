@@ -0,0 +1,178 @@
+package services
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	mysqlConfigs = []string{
+		"/etc/my.cnf",
+		"/etc/my.cnf.d/*.cnf",
+		"/etc/mysql/my.cnf",
+		"/etc/mysql/conf.d/*.cnf",
+		"/etc/mysql/mysql.conf.d/*.cnf",
+		"/etc/mysql/mariadb.conf.d/*.cnf",
+	}
+	postgresConfigs = []string{
+		"/etc/postgresql/*/main/postgresql.conf",
+		"/var/lib/pgsql/data/postgresql.conf",
+		"/var/lib/pgsql/*/data/postgresql.conf",
+		"/var/lib/postgresql/data/postgresql.conf",
+	}
+	redisConfigs = []string{
+		"/etc/redis/redis.conf",
+		"/etc/redis.conf",
+		"/etc/redis/*.conf",
+	}
+)
+
+const (
+	kib = 1024
+	mib = 1024 * kib
+)
+
+func mysqlBufferPool(string) (int, string) {
+	return findSetting(mysqlConfigs, "innodb_buffer_pool_size", "=", 1)
+}
+
+func postgresSharedBuffers(string) (int, string) {
+	// Bare shared_buffers values are 8kB pages
+	return findSetting(postgresConfigs, "shared_buffers", "=", 8*kib)
+}
+
+func redisMaxMemory(string) (int, string) {
+	return findSetting(redisConfigs, "maxmemory", " ", 1)
+}
+
+func memcachedMemory(cmdline string) (int, string) {
+	// memcached -m is in megabytes, 64 when omitted
+	if val := flagValue(cmdline, "-m"); val != "" {
+		return parseSize(val, mib), "memcached -m"
+	}
+	return 64, "memcached default"
+}
+
+func javaHeap(cmdline string) (int, string) {
+	for _, field := range strings.Fields(cmdline) {
+		if strings.HasPrefix(field, "-Xmx") {
+			return parseSize(strings.TrimPrefix(field, "-Xmx"), 1), "JVM -Xmx"
+		}
+	}
+	return 0, ""
+}
+
+func varnishStorage(cmdline string) (int, string) {
+	// -s malloc,256m or -s name=malloc,1G
+	val := flagValue(cmdline, "-s")
+	if _, size, ok := strings.Cut(val, "malloc,"); ok {
+		return parseSize(size, 1), "varnishd -s"
+	}
+	return 0, ""
+}
+
+// findSetting returns the last value of key across all config files, later
+// files override earlier ones like the daemons' own include order
+func findSetting(patterns []string, key, sep string, bareUnit int64) (int, string) {
+	var value, source string
+
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if v := readSetting(path, key, sep); v != "" {
+				value, source = v, path
+			}
+		}
+	}
+
+	if value == "" {
+		return 0, ""
+	}
+	return parseSize(value, bareUnit), source
+}
+
+func readSetting(path, key, sep string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var value string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		name, val, ok := strings.Cut(line, sep)
+		if !ok {
+			continue
+		}
+
+		// MySQL accepts dashes and underscores interchangeably
+		name = strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+		if name != key {
+			continue
+		}
+
+		val, _, _ = strings.Cut(val, "#")
+		value = strings.Trim(strings.TrimSpace(val), `"'`)
+	}
+
+	return value
+}
+
+func flagValue(cmdline, flag string) string {
+	fields := strings.Fields(cmdline)
+	for i, field := range fields {
+		if field == flag && i+1 < len(fields) {
+			return fields[i+1]
+		}
+		if strings.HasPrefix(field, flag) && len(field) > len(flag) {
+			return strings.TrimPrefix(field, flag)
+		}
+	}
+	return ""
+}
+
+// parseSize converts sizes like 512M, 2GB, 128mb or 8kB to megabytes; values
+// without a unit are multiplied by bareUnit bytes
+func parseSize(value string, bareUnit int64) int {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	i := 0
+	for i < len(value) && (value[i] >= '0' && value[i] <= '9' || value[i] == '.') {
+		i++
+	}
+
+	num, err := strconv.ParseFloat(value[:i], 64)
+	if err != nil {
+		return 0
+	}
+
+	multiplier := float64(bareUnit)
+	switch strings.TrimSuffix(value[i:], "b") {
+	case "":
+		if strings.HasSuffix(value, "b") && i < len(value) {
+			multiplier = 1
+		}
+	case "k":
+		multiplier = kib
+	case "m":
+		multiplier = mib
+	case "g":
+		multiplier = 1024 * mib
+	case "t":
+		multiplier = 1024 * 1024 * mib
+	default:
+		return 0
+	}
+
+	return int(num * multiplier / mib)
+}
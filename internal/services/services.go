@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Service is a co-located daemon competing with PHP for memory
+type Service struct {
+	Name         string `json:"name"`
+	ProcessCount int    `json:"process_count"`
+	RSSMB        int    `json:"rss_mb"`           // Current resident memory of all processes
+	PSSMB        int    `json:"pss_mb,omitempty"` // RSS with shared pages counted once (0 = unknown)
+	ConfiguredMB int    `json:"configured_mb"`    // Memory the daemon is configured to grow to (0 = unknown)
+	ConfigSource string `json:"config_source,omitempty"`
+}
+
+// UsedMB returns what the service currently uses. Backends and workers map
+// the same shared memory and text pages, so PSS is preferred over summed RSS.
+func (s Service) UsedMB() int {
+	if s.PSSMB > 0 {
+		return s.PSSMB
+	}
+	return s.RSSMB
+}
+
+// ReservedMB returns the memory to keep free for the service: its configured
+// ceiling when known, otherwise what it currently uses
+func (s Service) ReservedMB() int {
+	return max(s.ConfiguredMB, s.UsedMB())
+}
+
+// daemon describes how to recognise a service and read its memory config
+type daemon struct {
+	name       string
+	comms      []string
+	cmdline    string // Required cmdline substring (for generic comms like java)
+	configured func(cmdline string) (int, string)
+}
+
+var daemons = []daemon{
+	{name: "MySQL", comms: []string{"mysqld", "mariadbd"}, configured: mysqlBufferPool},
+	{name: "PostgreSQL", comms: []string{"postgres", "postmaster"}, configured: postgresSharedBuffers},
+	{name: "Redis", comms: []string{"redis-server"}, configured: redisMaxMemory},
+	{name: "Memcached", comms: []string{"memcached"}, configured: memcachedMemory},
+	{name: "Elasticsearch", comms: []string{"java"}, cmdline: "org.elasticsearch", configured: javaHeap},
+	{name: "Varnish", comms: []string{"varnishd", "cache-main"}, configured: varnishStorage},
+	{name: "nginx", comms: []string{"nginx"}},
+	{name: "Apache", comms: []string{"apache2", "httpd"}},
+}
+
+// Detect scans /proc for known daemons and estimates their memory footprint;
+// the scan stops once ctx is done
+func Detect(ctx context.Context) ([]Service, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]*Service{}
+	cmdlines := map[string]string{}
+	rssKB := map[string]int64{}
+	pssKB := map[string]int64{}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		comm := readProcFile(pid, "comm")
+		if comm == "" {
+			continue
+		}
+		cmdline := strings.ReplaceAll(readProcFile(pid, "cmdline"), "\x00", " ")
+
+		d := matchDaemon(comm, cmdline)
+		if d == nil {
+			continue
+		}
+
+		svc, ok := found[d.name]
+		if !ok {
+			svc = &Service{Name: d.name}
+			found[d.name] = svc
+			cmdlines[d.name] = cmdline
+		}
+		svc.ProcessCount++
+		rssKB[d.name] += readProcKB(pid, "status", "VmRSS:")
+		pssKB[d.name] += readProcKB(pid, "smaps_rollup", "Pss:")
+	}
+
+	result := make([]Service, 0, len(found))
+	for _, d := range daemons {
+		svc, ok := found[d.name]
+		if !ok {
+			continue
+		}
+		svc.RSSMB = int(rssKB[d.name] / 1024)
+		svc.PSSMB = int(pssKB[d.name] / 1024) // smaps_rollup needs Linux 4.14+
+		if d.configured != nil {
+			svc.ConfiguredMB, svc.ConfigSource = d.configured(cmdlines[d.name])
+		}
+		result = append(result, *svc)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ReservedMB() > result[j].ReservedMB()
+	})
+
+	return result, nil
+}
+
+func matchDaemon(comm, cmdline string) *daemon {
+	for i := range daemons {
+		d := &daemons[i]
+		for _, c := range d.comms {
+			if comm != c {
+				continue
+			}
+			if d.cmdline != "" && !strings.Contains(cmdline, d.cmdline) {
+				continue
+			}
+			return d
+		}
+	}
+	return nil
+}
+
+func readProcFile(pid int, name string) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readProcKB reads a "<key> <n> kB" line of a /proc/<pid> file
+func readProcKB(pid int, name, key string) int64 {
+	file, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), name))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == key {
+			val, _ := strconv.ParseInt(fields[1], 10, 64)
+			return val
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,49 @@
+package snapshot
+
+import "fmt"
+
+// migrations upgrade a raw snapshot from version n (index) to n+1
+var migrations = []func(raw map[string]any){
+	migrateV0,
+	migrateV1,
+}
+
+// migrate upgrades a raw snapshot document in place to the current version
+func migrate(raw map[string]any) error {
+	version := 0
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+
+	if version > Version {
+		return fmt.Errorf("snapshot version %d is newer than supported version %d", version, Version)
+	}
+
+	for ; version < Version; version++ {
+		migrations[version](raw)
+		raw["version"] = version + 1
+	}
+
+	return nil
+}
+
+// migrateV0 wraps a bare system.Info document (as used by --system-from)
+// into a version 1 snapshot
+func migrateV0(raw map[string]any) {
+	system := map[string]any{}
+	for key, value := range raw {
+		system[key] = value
+		delete(raw, key)
+	}
+	raw["system"] = system
+}
+
+// migrateV1 adds the Apache, RoadRunner and Swoole processes of version 2;
+// version 1 only captured PHP-FPM, so none are known
+func migrateV1(raw map[string]any) {
+	for _, key := range []string{"apache_processes", "roadrunner_workers", "swoole_workers"} {
+		if _, ok := raw[key]; !ok {
+			raw[key] = map[string]any{}
+		}
+	}
+}
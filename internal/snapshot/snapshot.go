@@ -0,0 +1,140 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Version is the current snapshot format version
+const Version = 2
+
+// Snapshot is everything the calculators read from a host, captured so
+// configs can be computed elsewhere with identical results
+type Snapshot struct {
+	Version    int                `json:"version"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Hostname   string             `json:"hostname"`
+	System     *system.Info       `json:"system"`
+	Processes  *php.ProcessInfo   `json:"processes"`
+	Apache     *php.ProcessInfo   `json:"apache_processes"`
+	RoadRunner *php.ProcessInfo   `json:"roadrunner_workers"`
+	Swoole     *php.ProcessInfo   `json:"swoole_workers"`
+	Settings   map[string]string  `json:"php_settings"`
+	Services   []services.Service `json:"services"`
+	Status     *php.Status        `json:"status,omitempty"`
+}
+
+// Capture detects the current host; statusURL is optional
+func Capture(ctx context.Context, statusURL string) (*Snapshot, error) {
+	sysInfo, err := system.Detect()
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		System:    sysInfo,
+		Settings:  map[string]string{},
+	}
+	snap.Hostname, _ = os.Hostname()
+
+	if snap.Processes, err = php.DetectProcesses(ctx); err != nil {
+		return nil, fmt.Errorf("failed to detect PHP processes: %w", err)
+	}
+	if snap.Apache, err = php.DetectApacheProcesses(ctx); err != nil {
+		return nil, fmt.Errorf("failed to detect Apache processes: %w", err)
+	}
+	if snap.RoadRunner, err = php.DetectRoadRunnerWorkers(ctx); err != nil {
+		return nil, fmt.Errorf("failed to detect RoadRunner workers: %w", err)
+	}
+	if snap.Swoole, err = php.DetectSwooleWorkers(ctx); err != nil {
+		return nil, fmt.Errorf("failed to detect Swoole workers: %w", err)
+	}
+
+	// The php CLI and services are optional on the host
+	if settings, err := php.GetSettings(ctx); err == nil {
+		snap.Settings = settings
+	}
+	if snap.Services, err = services.Detect(ctx); err != nil {
+		return nil, fmt.Errorf("failed to detect services: %w", err)
+	}
+
+	if statusURL != "" {
+		if snap.Status, err = php.FetchStatus(statusURL); err != nil {
+			return nil, fmt.Errorf("failed to fetch status page: %w", err)
+		}
+	}
+
+	return snap, nil
+}
+
+// MemoryLimitMB returns the captured PHP memory_limit (0 = unknown)
+func (s *Snapshot) MemoryLimitMB() int {
+	limit, ok := s.Settings["memory_limit"]
+	if !ok {
+		return 0
+	}
+
+	mb, err := php.ParseMemoryLimit(limit)
+	if err != nil {
+		return 0
+	}
+	return mb
+}
+
+// Write encodes the snapshot as indented JSON
+func (s *Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// Load reads a snapshot file, migrating older formats to the current version
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]any{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := migrate(raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Re-encode the migrated document into the typed snapshot
+	if data, err = json.Marshal(raw); err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if snap.System == nil {
+		return nil, fmt.Errorf("%s: snapshot has no system information", path)
+	}
+	for _, procs := range []**php.ProcessInfo{&snap.Processes, &snap.Apache, &snap.RoadRunner, &snap.Swoole} {
+		if *procs == nil {
+			*procs = &php.ProcessInfo{}
+		}
+	}
+	if snap.Settings == nil {
+		snap.Settings = map[string]string{}
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,49 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMigrates(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"bare system info", `{"cpu_cores": 4, "mem_total_mb": 8192}`},
+		{"version 1", `{"version": 1, "system": {"cpu_cores": 4, "mem_total_mb": 8192},
+			"processes": {"process_count": 3}, "php_settings": {"memory_limit": "256M"}}`},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "host.json")
+		if err := os.WriteFile(path, []byte(tt.doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		snap, err := Load(path)
+		if err != nil {
+			t.Errorf("%s: Load: %v", tt.name, err)
+			continue
+		}
+		if snap.Version != Version {
+			t.Errorf("%s: version = %d, want %d", tt.name, snap.Version, Version)
+		}
+		if snap.System.CPUCores != 4 || snap.System.MemTotalMB != 8192 {
+			t.Errorf("%s: system = %+v, want 4 cores and 8192 MB", tt.name, snap.System)
+		}
+		if snap.Processes == nil || snap.Apache == nil || snap.RoadRunner == nil || snap.Swoole == nil {
+			t.Errorf("%s: processes not initialized: %+v", tt.name, snap)
+		}
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "host.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99, "system": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load accepted a snapshot from a newer version")
+	}
+}
@@ -0,0 +1,151 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// Cgroup holds the resource limits of the cgroup the tuner runs in
+type Cgroup struct {
+	Version      int     `json:"version"`
+	Path         string  `json:"path"`
+	MemoryMaxMB  int     `json:"memory_max_mb"`  // 0 = unlimited
+	MemoryHighMB int     `json:"memory_high_mb"` // 0 = unlimited (v2 only)
+	CPUQuota     float64 `json:"cpu_quota"`      // CPU cores, 0 = unlimited
+}
+
+// Limited reports whether the cgroup restricts memory or CPU
+func (c *Cgroup) Limited() bool {
+	return c != nil && (c.MemoryMaxMB > 0 || c.MemoryHighMB > 0 || c.CPUQuota > 0)
+}
+
+// DetectCgroup reads memory and CPU limits for the current process, returns
+// nil when no cgroup hierarchy is mounted
+func DetectCgroup() *Cgroup {
+	paths := readCgroupPaths()
+
+	// cgroup v2 (unified hierarchy)
+	if path, ok := paths[""]; ok {
+		dir := filepath.Join(cgroupRoot, path)
+		if _, err := os.Stat(filepath.Join(dir, "memory.max")); err == nil {
+			cg := &Cgroup{Version: 2, Path: path}
+			cg.MemoryMaxMB = readLimitMB(filepath.Join(dir, "memory.max"))
+			cg.MemoryHighMB = readLimitMB(filepath.Join(dir, "memory.high"))
+			cg.CPUQuota = readCPUMax(filepath.Join(dir, "cpu.max"))
+			return cg
+		}
+	}
+
+	// cgroup v1
+	memPath, ok := paths["memory"]
+	if !ok {
+		return nil
+	}
+
+	cg := &Cgroup{Version: 1, Path: memPath}
+	cg.MemoryMaxMB = readLimitMB(cgroupV1File("memory", memPath, "memory.limit_in_bytes"))
+
+	quota := readInt(cgroupV1File("cpu", paths["cpu"], "cpu.cfs_quota_us"))
+	period := readInt(cgroupV1File("cpu", paths["cpu"], "cpu.cfs_period_us"))
+	if quota > 0 && period > 0 {
+		cg.CPUQuota = float64(quota) / float64(period)
+	}
+
+	return cg
+}
+
+// readCgroupPaths maps controllers to paths from /proc/self/cgroup, the
+// unified v2 hierarchy is stored under ""
+func readCgroupPaths() map[string]string {
+	paths := map[string]string{}
+
+	file, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return paths
+	}
+	defer file.Close()
+
+	// Format: hierarchy-ID:controller-list:path
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+	}
+
+	return paths
+}
+
+// cgroupV1File resolves a controller file, falling back to the controller
+// root when the process path is not visible (e.g. inside containers)
+func cgroupV1File(controller, path, name string) string {
+	file := filepath.Join(cgroupRoot, controller, path, name)
+	if _, err := os.Stat(file); err == nil {
+		return file
+	}
+	return filepath.Join(cgroupRoot, controller, name)
+}
+
+func readLimitMB(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0
+	}
+
+	bytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	// v1 reports "unlimited" as a huge page-aligned number
+	if bytes >= 1<<62 {
+		return 0
+	}
+
+	return int(bytes / 1024 / 1024)
+}
+
+func readCPUMax(path string) float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	// Format: "$MAX $PERIOD" or "max $PERIOD"
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || period == 0 {
+		return 0
+	}
+
+	return quota / period
+}
+
+func readInt(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	val, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return val
+}
@@ -0,0 +1,94 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Simulate returns an idle machine with the given shape, using kernel
+// defaults for everything that cannot be derived from CPU and memory
+func Simulate(cpuCores, memTotalMB int) *Info {
+	return &Info{
+		Platform:        "simulated",
+		CPUCores:        cpuCores,
+		MemTotalMB:      memTotalMB,
+		MemFreeMB:       memTotalMB,
+		MemAvailMB:      memTotalMB,
+		OvercommitRatio: 50,
+		Swappiness:      60,
+		SoMaxConn:       4096,
+	}
+}
+
+// Load reads system information from a JSON file
+func Load(path string) (*Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if info.CPUCores <= 0 || info.MemTotalMB <= 0 {
+		return nil, fmt.Errorf("%s: cpu_cores and mem_total_mb are required", path)
+	}
+
+	if info.MemAvailMB == 0 {
+		info.MemAvailMB = info.MemTotalMB - info.MemUsedMB
+	}
+	if info.Platform == "" {
+		info.Platform = "simulated"
+	}
+
+	return info, nil
+}
+
+// ParseShape parses a machine shape like "4x8G" into CPU cores and memory in MB
+func ParseShape(shape string) (int, int, error) {
+	cpu, mem, ok := strings.Cut(strings.ToLower(strings.TrimSpace(shape)), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid shape %q (expected <cpu>x<memory>, e.g. 4x8G)", shape)
+	}
+
+	cores, err := strconv.Atoi(cpu)
+	if err != nil || cores <= 0 {
+		return 0, 0, fmt.Errorf("invalid CPU count in shape %q", shape)
+	}
+
+	memMB, err := ParseSizeMB(mem)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid memory in shape %q: %w", shape, err)
+	}
+
+	return cores, memMB, nil
+}
+
+// ParseSizeMB parses a memory size like 512, 512M, 8G or 1T into megabytes;
+// values without a unit are megabytes
+func ParseSizeMB(value string) (int, error) {
+	size := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "B")
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(size, "T"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(size, "G"):
+		multiplier = 1024
+	case strings.HasSuffix(size, "M"):
+	default:
+		size += "M"
+	}
+
+	val, err := strconv.ParseFloat(size[:len(size)-1], 64)
+	if err != nil || val <= 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+
+	return int(val * multiplier), nil
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -11,12 +12,40 @@ import (
 
 // Info holds system resource information
 type Info struct {
-	CPUCores   int
-	MemTotalMB int
-	MemFreeMB  int
-	MemAvailMB int
-	MemUsedMB  int
-	Platform   string
+	CPUCores   int    `json:"cpu_cores"`
+	MemTotalMB int    `json:"mem_total_mb"`
+	MemFreeMB  int    `json:"mem_free_mb"`
+	MemAvailMB int    `json:"mem_avail_mb"`
+	MemUsedMB  int    `json:"mem_used_mb"`
+	Platform   string `json:"platform"`
+
+	// Swap and kernel memory policy
+	SwapTotalMB     int  `json:"swap_total_mb"`
+	SwapFreeMB      int  `json:"swap_free_mb"`
+	CommitLimitMB   int  `json:"commit_limit_mb"`
+	CommittedMB     int  `json:"committed_mb"`
+	Zram            bool `json:"zram"`             // zram device in use as swap
+	Zswap           bool `json:"zswap"`            // zswap compressed swap cache enabled
+	Overcommit      int  `json:"overcommit"`       // vm.overcommit_memory (0 heuristic, 1 always, 2 strict)
+	OvercommitRatio int  `json:"overcommit_ratio"` // vm.overcommit_ratio
+	Swappiness      int  `json:"swappiness"`       // vm.swappiness
+
+	// SoMaxConn caps listen backlogs (net.core.somaxconn), 0 when unknown
+	SoMaxConn int `json:"somaxconn,omitempty"`
+
+	// MemPressure is nil when PSI is not available
+	MemPressure *Pressure `json:"mem_pressure,omitempty"`
+
+	// Cgroup is nil when no cgroup hierarchy is mounted
+	Cgroup *Cgroup `json:"cgroup,omitempty"`
+}
+
+// Pressure holds PSI averages from /proc/pressure/memory
+type Pressure struct {
+	SomeAvg10 float64 `json:"some_avg10"`
+	SomeAvg60 float64 `json:"some_avg60"`
+	FullAvg10 float64 `json:"full_avg10"`
+	FullAvg60 float64 `json:"full_avg60"`
 }
 
 // Detect gathers system information
@@ -59,8 +88,19 @@ func Detect() (*Info, error) {
 			info.MemFreeMB = valueMB
 		case strings.HasPrefix(line, "MemAvailable:"):
 			info.MemAvailMB = valueMB
+		case strings.HasPrefix(line, "SwapTotal:"):
+			info.SwapTotalMB = valueMB
+		case strings.HasPrefix(line, "SwapFree:"):
+			info.SwapFreeMB = valueMB
+		case strings.HasPrefix(line, "CommitLimit:"):
+			info.CommitLimitMB = valueMB
+		case strings.HasPrefix(line, "Committed_AS:"):
+			info.CommittedMB = valueMB
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
 	if info.MemAvailMB == 0 {
 		info.MemAvailMB = info.MemFreeMB
@@ -68,5 +108,87 @@ func Detect() (*Info, error) {
 
 	info.MemUsedMB = info.MemTotalMB - info.MemAvailMB
 
-	return info, scanner.Err()
+	// Kernel memory policy, missing files keep the kernel defaults
+	info.Overcommit = readSysctl("vm/overcommit_memory", 0)
+	info.OvercommitRatio = readSysctl("vm/overcommit_ratio", 50)
+	info.Swappiness = readSysctl("vm/swappiness", 60)
+	info.SoMaxConn = readSysctl("net/core/somaxconn", 0)
+
+	info.Zram = detectZram()
+	info.Zswap = detectZswap()
+	info.MemPressure = readMemoryPressure()
+	info.Cgroup = DetectCgroup()
+
+	return info, nil
+}
+
+func readSysctl(name string, fallback int) int {
+	data, err := os.ReadFile(filepath.Join("/proc/sys", name))
+	if err != nil {
+		return fallback
+	}
+
+	val, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fallback
+	}
+
+	return val
+}
+
+func detectZram() bool {
+	data, err := os.ReadFile("/proc/swaps")
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(data), "/dev/zram")
+}
+
+func detectZswap() bool {
+	data, err := os.ReadFile("/sys/module/zswap/parameters/enabled")
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(data)) == "Y"
+}
+
+func readMemoryPressure() *Pressure {
+	file, err := os.Open("/proc/pressure/memory")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	psi := &Pressure{}
+
+	// Format: some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		avg10 := parsePressureField(fields[1], "avg10=")
+		avg60 := parsePressureField(fields[2], "avg60=")
+
+		switch fields[0] {
+		case "some":
+			psi.SomeAvg10, psi.SomeAvg60 = avg10, avg60
+		case "full":
+			psi.FullAvg10, psi.FullAvg60 = avg10, avg60
+		}
+	}
+
+	return psi
+}
+
+func parsePressureField(field, prefix string) float64 {
+	val, err := strconv.ParseFloat(strings.TrimPrefix(field, prefix), 64)
+	if err != nil {
+		return 0
+	}
+	return val
 }
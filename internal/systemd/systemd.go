@@ -0,0 +1,110 @@
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+)
+
+// Header marks drop-ins written by php-tuner, which may be overwritten
+const Header = "# Generated by php-tuner"
+
+var (
+	// Unit search path, from local overrides to vendor units
+	unitDirs = []string{
+		"/etc/systemd/system",
+		"/run/systemd/system",
+		"/usr/local/lib/systemd/system",
+		"/usr/lib/systemd/system",
+		"/lib/systemd/system",
+	}
+	fpmUnits = []string{
+		"php*-fpm.service",     // Debian, Ubuntu: php8.3-fpm.service
+		"php-fpm.service",      // RHEL, Fedora, Arch
+		"php*-php-fpm.service", // Remi: php83-php-fpm.service
+		"php-fpm*.service",     // Alpine: php-fpm83.service
+	}
+	frankenphpUnits = []string{
+		"frankenphp.service",
+	}
+)
+
+// Local drop-ins take precedence over vendor ones
+const dropInDir = "/etc/systemd/system"
+
+// ErrNotFound is returned when no unit file of the runtime is installed
+var ErrNotFound = errors.New("no systemd unit found")
+
+// FindFPMUnit returns the installed PHP-FPM unit, the highest PHP version
+// when several are installed
+func FindFPMUnit() (string, error) {
+	return findUnit(fpmUnits)
+}
+
+// FindFrankenPHPUnit returns the installed FrankenPHP unit
+func FindFrankenPHPUnit() (string, error) {
+	return findUnit(frankenphpUnits)
+}
+
+func findUnit(patterns []string) (string, error) {
+	for _, pattern := range patterns {
+		var names []string
+		for _, dir := range unitDirs {
+			matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+			for _, match := range matches {
+				names = append(names, filepath.Base(match))
+			}
+		}
+		if len(names) > 0 {
+			// Highest PHP version sorts last
+			slices.Sort(names)
+			return names[len(names)-1], nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// DropInPath returns the override drop-in of a unit
+func DropInPath(unit string) string {
+	return filepath.Join(dropInDir, unit+".d", "override.conf")
+}
+
+// Render formats settings as a [Service] drop-in
+func Render(settings []calculator.Directive) string {
+	var b strings.Builder
+	b.WriteString(Header + "\n")
+	b.WriteString("[Service]\n")
+	for _, s := range settings {
+		fmt.Fprintf(&b, "%s=%s\n", s.Name, s.Value)
+	}
+	return b.String()
+}
+
+// Apply writes the drop-in and reloads systemd. An override.conf that
+// php-tuner did not write, e.g. from systemctl edit, is left alone.
+func Apply(unit string, settings []calculator.Directive) (string, error) {
+	path := DropInPath(unit)
+
+	if data, err := os.ReadFile(path); err == nil && !strings.HasPrefix(string(data), Header) {
+		return path, fmt.Errorf("%s was not written by php-tuner, merge the settings manually", path)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return path, err
+	}
+	if err := os.WriteFile(path, []byte(Render(settings)), 0o644); err != nil {
+		return path, err
+	}
+
+	out, err := exec.Command("systemctl", "daemon-reload").CombinedOutput()
+	if err != nil {
+		return path, fmt.Errorf("systemctl daemon-reload: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return path, nil
+}
@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/syslog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sink receives watch events
+type Sink interface {
+	Send(Event) error
+}
+
+// NewSinks parses a comma separated list of outputs: "stdout", "syslog" or
+// an http(s) webhook URL
+func NewSinks(spec string, stdout io.Writer) ([]Sink, error) {
+	var sinks []Sink
+
+	for _, target := range strings.Split(spec, ",") {
+		target = strings.TrimSpace(target)
+
+		switch {
+		case target == "" || target == "stdout":
+			sinks = append(sinks, &JSONLinesSink{W: stdout})
+		case target == "syslog":
+			w, err := syslog.New(syslog.LOG_WARNING|syslog.LOG_DAEMON, "php-tuner")
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+			}
+			sinks = append(sinks, &JSONLinesSink{W: w})
+		case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+			sinks = append(sinks, &WebhookSink{URL: target, Client: &http.Client{Timeout: 10 * time.Second}})
+		default:
+			return nil, fmt.Errorf("unknown output %q (expected stdout, syslog or a webhook URL)", target)
+		}
+	}
+
+	return sinks, nil
+}
+
+// JSONLinesSink writes one JSON document per event and line
+type JSONLinesSink struct {
+	W io.Writer
+}
+
+// Send writes the event as a single JSON line
+func (s *JSONLinesSink) Send(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = s.W.Write(append(data, '\n'))
+	return err
+}
+
+// WebhookSink posts each event as JSON to a URL
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// Send posts the event and fails on non-2xx responses
+func (s *WebhookSink) Send(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Post(s.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
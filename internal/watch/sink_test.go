@@ -0,0 +1,46 @@
+package watch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookSinkSend(t *testing.T) {
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("content type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sink := &WebhookSink{URL: srv.URL, Client: srv.Client()}
+	event := Event{Type: EventDrift, Runtime: "fpm", Message: "pm.max_children drifted"}
+	if err := sink.Send(event); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.Type != event.Type || got.Runtime != event.Runtime || got.Message != event.Message {
+		t.Errorf("received %+v, want %+v", got, event)
+	}
+}
+
+func TestWebhookSinkSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	sink := &WebhookSink{URL: srv.URL, Client: srv.Client()}
+	if err := sink.Send(Event{Type: EventError}); err == nil {
+		t.Fatal("Send succeeded on a 500 response")
+	}
+}
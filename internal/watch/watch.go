@@ -0,0 +1,243 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/deployed"
+	"github.com/muuvmuuv/php-tuner/internal/diff"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// Event types
+const (
+	EventMaxChildrenReached = "max_children_reached"
+	EventMemoryOverBudget   = "memory_over_budget"
+	EventDrift              = "drift"
+	EventError              = "error"
+)
+
+// Runtimes supported by the watcher
+const (
+	RuntimeFPM        = "php-fpm"
+	RuntimeFrankenPHP = "frankenphp"
+)
+
+// Event is emitted when the watcher detects saturation or drift
+type Event struct {
+	Time    time.Time      `json:"time"`
+	Type    string         `json:"type"`
+	Runtime string         `json:"runtime"`
+	Message string         `json:"message"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// Config controls what the watcher checks
+type Config struct {
+	Runtime        string
+	Interval       time.Duration
+	StatusURL      string  // PHP-FPM status page, empty disables max children checks
+	Current        string  // Deployed config file, empty disables drift checks
+	Pool           string  // PHP-FPM pool to compare
+	DriftThreshold float64 // Drift in percent that triggers an event
+	FPM            calculator.Options
+	FrankenPHP     calculator.FrankenPHPOptions
+	Services       []services.Service // Detected once at startup
+	MemoryLimitMB  int                // PHP memory_limit (0 = unknown)
+}
+
+// Watcher periodically re-detects the host and emits events to its sinks
+type Watcher struct {
+	cfg   Config
+	sinks []Sink
+
+	// State of the previous check, events only fire on changes
+	lastMaxChildren int64
+	overBudget      bool
+	lastDrift       string
+}
+
+// New creates a watcher
+func New(cfg Config, sinks []Sink) *Watcher {
+	return &Watcher{cfg: cfg, sinks: sinks, lastMaxChildren: -1}
+}
+
+// Run checks immediately and then on every interval until ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Check(ctx); err != nil {
+			w.emit(EventError, err.Error(), nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check runs a single detection cycle
+func (w *Watcher) Check(ctx context.Context) error {
+	sysInfo, err := system.Detect()
+	if err != nil {
+		return err
+	}
+
+	if w.cfg.Runtime == RuntimeFrankenPHP {
+		return w.checkFrankenPHP(ctx, sysInfo)
+	}
+	return w.checkFPM(ctx, sysInfo)
+}
+
+func (w *Watcher) checkFPM(ctx context.Context, sysInfo *system.Info) error {
+	procs, err := php.DetectProcesses(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg := calculator.Calculate(calculator.Input{System: sysInfo, PHP: procs, Services: w.cfg.Services,
+		MemoryLimitMB: w.cfg.MemoryLimitMB}, w.cfg.FPM)
+	valid := w.validate(cfg.Validate())
+	w.checkMemory(procs, cfg.AvailableMemoryMB)
+
+	if w.cfg.StatusURL != "" {
+		// An unreachable status page must not disable the drift check
+		status, err := php.FetchStatus(w.cfg.StatusURL)
+		if err != nil {
+			w.emit(EventError, fmt.Sprintf("status page: %v", err), nil)
+		} else {
+			w.checkMaxChildren(status)
+		}
+	}
+
+	if w.cfg.Current != "" && valid {
+		current, err := deployed.ParseFPMPool(w.cfg.Current, w.cfg.Pool)
+		if err != nil {
+			return err
+		}
+		w.checkDrift(diff.FPM(current, cfg))
+	}
+
+	return nil
+}
+
+func (w *Watcher) checkFrankenPHP(ctx context.Context, sysInfo *system.Info) error {
+	procs, err := php.DetectProcessesMatching(ctx, "frankenphp")
+	if err != nil {
+		return err
+	}
+
+	cfg := calculator.CalculateFrankenPHP(calculator.Input{System: sysInfo, Services: w.cfg.Services}, w.cfg.FrankenPHP)
+	valid := w.validate(cfg.Validate())
+	w.checkMemory(procs, cfg.AvailableMemoryMB)
+
+	if w.cfg.Current != "" && valid {
+		current, err := deployed.ParseCaddyfile(w.cfg.Current)
+		if err != nil {
+			return err
+		}
+		w.checkDrift(diff.FrankenPHP(current, cfg))
+	}
+
+	return nil
+}
+
+// validate reports an invalid recommendation, which is not compared with
+// the deployed config
+func (w *Watcher) validate(err error) bool {
+	if err != nil {
+		w.emit(EventError, err.Error(), nil)
+		return false
+	}
+	return true
+}
+
+func (w *Watcher) checkMaxChildren(status *php.Status) {
+	last := w.lastMaxChildren
+	w.lastMaxChildren = status.MaxChildrenReached
+
+	if last < 0 || status.MaxChildrenReached <= last {
+		return
+	}
+
+	w.emit(EventMaxChildrenReached,
+		fmt.Sprintf("pool %s hit pm.max_children %d more time(s)", status.Pool, status.MaxChildrenReached-last),
+		map[string]any{
+			"pool":                 status.Pool,
+			"max_children_reached": status.MaxChildrenReached,
+			"listen_queue":         status.ListenQueue,
+			"active_processes":     status.ActiveProcesses,
+		})
+}
+
+func (w *Watcher) checkMemory(procs *php.ProcessInfo, budgetMB int) {
+	over := procs.TotalMemMB > float64(budgetMB)
+	if over && !w.overBudget {
+		w.emit(EventMemoryOverBudget,
+			fmt.Sprintf("PHP uses %.0f MB, above the %d MB budget", procs.TotalMemMB, budgetMB),
+			map[string]any{
+				"used_mb":       procs.TotalMemMB,
+				"budget_mb":     budgetMB,
+				"process_count": procs.ProcessCount,
+			})
+	}
+	w.overBudget = over
+}
+
+func (w *Watcher) checkDrift(changes []diff.Change) {
+	drift := diff.MaxDrift(changes)
+	if drift <= w.cfg.DriftThreshold {
+		w.lastDrift = ""
+		return
+	}
+
+	directives := map[string]any{}
+	var signature []string
+	for _, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+		directives[c.Directive] = map[string]string{"current": c.Current, "recommended": c.Recommended}
+		signature = append(signature, c.Directive+"="+c.Current+">"+c.Recommended)
+	}
+	sort.Strings(signature)
+
+	// Only report again when the recommendation or deployed config changes
+	sig := strings.Join(signature, ",")
+	if sig == w.lastDrift {
+		return
+	}
+	w.lastDrift = sig
+
+	w.emit(EventDrift,
+		fmt.Sprintf("deployed config drifts %.0f%% from the recommendation", drift),
+		map[string]any{"drift_pct": drift, "directives": directives})
+}
+
+func (w *Watcher) emit(eventType, message string, data map[string]any) {
+	event := Event{
+		Time:    time.Now().UTC(),
+		Type:    eventType,
+		Runtime: w.cfg.Runtime,
+		Message: message,
+		Data:    data,
+	}
+
+	for _, sink := range w.sinks {
+		// A failing sink must not stop the others or the watcher
+		if err := sink.Send(event); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not deliver %s event: %v\n", eventType, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package phptuner
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// api lists the exported types and functions whose shape is frozen for
+// APIVersion. The aliased internal types are included field by field, so a
+// change in the calculator shows up here.
+var api = []any{
+	Input{}, SystemInfo{}, ProcessInfo{}, Process{}, Service{},
+	Profile{}, Directive{}, Decision{}, ReservedItem{},
+	FPMOptions{}, FPMConfig{},
+	FrankenPHPOptions{}, FrankenPHPConfig{}, WorkerSpec{}, Worker{},
+	ApacheOptions{}, ApacheConfig{},
+	RoadRunnerOptions{}, RoadRunnerConfig{},
+	SwooleOptions{}, SwooleConfig{},
+	Detectors{}, StaticSystem{}, StaticProcesses{}, HostProcesses{},
+
+	LookupProfile, LoadProfiles, Simulate, HostDetectors,
+	DefaultFPMOptions, DefaultFrankenPHPOptions, DefaultApacheOptions,
+	DefaultRoadRunnerOptions, DefaultSwooleOptions,
+	CalculateFPM, CalculateFrankenPHP, CalculateApache, CalculateRoadRunner, CalculateSwoole,
+}
+
+// constants lists the exported constants with their type and value
+var constants = []struct {
+	name  string
+	value any
+}{
+	{"PMStatic", PMStatic}, {"PMDynamic", PMDynamic}, {"PMOnDemand", PMOnDemand},
+	{"FrameworkNone", FrameworkNone}, {"FrameworkLaravelOctane", FrameworkLaravelOctane},
+	{"FrameworkSymfony", FrameworkSymfony},
+}
+
+// describeAPI lists the fields and methods of the API types and of every
+// struct of this module they reach, and the function signatures
+func describeAPI() string {
+	var b strings.Builder
+	seen := map[reflect.Type]bool{}
+	var queue []reflect.Type
+
+	// visit queues the module's structs inside t
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			visit(t.Elem())
+		case reflect.Struct:
+			if !seen[t] && strings.HasPrefix(t.PkgPath(), "github.com/muuvmuuv/php-tuner/") {
+				seen[t] = true
+				queue = append(queue, t)
+			}
+		}
+	}
+
+	for _, v := range api {
+		t := reflect.TypeOf(v)
+		if t.Kind() == reflect.Func {
+			name := runtime.FuncForPC(reflect.ValueOf(v).Pointer()).Name()
+			fmt.Fprintf(&b, "func %s%s\n", name[strings.LastIndex(name, ".")+1:], strings.TrimPrefix(t.String(), "func"))
+			for i := range t.NumIn() {
+				visit(t.In(i))
+			}
+			for i := range t.NumOut() {
+				visit(t.Out(i))
+			}
+			continue
+		}
+		visit(t)
+	}
+
+	for _, c := range constants {
+		fmt.Fprintf(&b, "const %s %T = %q\n", c.name, c.value, c.value)
+	}
+
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+
+		fmt.Fprintf(&b, "type %s\n", t)
+		for i := range t.NumField() {
+			if f := t.Field(i); f.IsExported() {
+				fmt.Fprintf(&b, "\t%s %s\n", f.Name, f.Type)
+				visit(f.Type)
+			}
+		}
+		ptr := reflect.PointerTo(t)
+		for i := range ptr.NumMethod() {
+			m := ptr.Method(i)
+			fmt.Fprintf(&b, "\tfunc %s%s\n", m.Name, signature(m.Type))
+		}
+	}
+	return b.String()
+}
+
+// signature formats a method type without its receiver
+func signature(t reflect.Type) string {
+	in := make([]string, 0, t.NumIn())
+	for i := 1; i < t.NumIn(); i++ {
+		in = append(in, t.In(i).String())
+	}
+	out := make([]string, 0, t.NumOut())
+	for i := range t.NumOut() {
+		out = append(out, t.Out(i).String())
+	}
+	sig := "(" + strings.Join(in, ", ") + ")"
+	switch len(out) {
+	case 0:
+	case 1:
+		sig += " " + out[0]
+	default:
+		sig += " (" + strings.Join(out, ", ") + ")"
+	}
+	return sig
+}
+
+// TestAPIFrozen fails when the exported API changes. Bump APIVersion
+// deliberately, then rewrite the golden file with go test -update.
+func TestAPIFrozen(t *testing.T) {
+	const path = "testdata/api.golden"
+	got := "version " + APIVersion + "\n" + describeAPI()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !*update {
+		t.Fatal(err)
+	}
+	want := string(data)
+	if got == want {
+		return
+	}
+
+	version, _, _ := strings.Cut(want, "\n")
+	if version == "version "+APIVersion {
+		t.Fatalf("the exported API changed without a new APIVersion (%s); bump it and run go test -update", APIVersion)
+	}
+	if !*update {
+		t.Fatalf("%s is outdated, run go test -update", path)
+	}
+	if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
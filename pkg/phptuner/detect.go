@@ -0,0 +1,156 @@
+package phptuner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// SystemDetector provides the CPU, memory and kernel settings to tune for
+type SystemDetector interface {
+	DetectSystem(ctx context.Context) (*SystemInfo, error)
+}
+
+// ProcessDetector provides the running PHP processes and their memory
+type ProcessDetector interface {
+	DetectProcesses(ctx context.Context) (*ProcessInfo, error)
+}
+
+// ServiceDetector provides co-located services to reserve memory for
+type ServiceDetector interface {
+	DetectServices(ctx context.Context) ([]Service, error)
+}
+
+// MemoryLimitDetector provides PHP's memory_limit in MB (-1 = unlimited)
+type MemoryLimitDetector interface {
+	DetectMemoryLimit(ctx context.Context) (int, error)
+}
+
+// HostSystem reads /proc, /sys and cgroups of the current host
+type HostSystem struct{}
+
+// DetectSystem implements SystemDetector
+func (HostSystem) DetectSystem(ctx context.Context) (*SystemInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return system.Detect()
+}
+
+// HostProcesses lists processes on the current host
+type HostProcesses struct {
+	Pattern string // Regular expression for the command name (default: PHP-FPM)
+}
+
+// DetectProcesses implements ProcessDetector
+func (h HostProcesses) DetectProcesses(ctx context.Context) (*ProcessInfo, error) {
+	if h.Pattern == "" {
+		return php.DetectProcesses(ctx)
+	}
+	return php.DetectProcessesMatching(ctx, h.Pattern)
+}
+
+// HostServices finds databases, caches and JVMs on the current host
+type HostServices struct{}
+
+// DetectServices implements ServiceDetector
+func (HostServices) DetectServices(ctx context.Context) ([]Service, error) {
+	return services.Detect(ctx)
+}
+
+// HostMemoryLimit asks the php binary in PATH for its memory_limit
+type HostMemoryLimit struct{}
+
+// DetectMemoryLimit implements MemoryLimitDetector
+func (HostMemoryLimit) DetectMemoryLimit(ctx context.Context) (int, error) {
+	return php.GetPHPMemoryLimit(ctx)
+}
+
+// StaticSystem returns a fixed machine, e.g. from Simulate or a snapshot
+type StaticSystem struct {
+	Info *SystemInfo
+}
+
+// DetectSystem implements SystemDetector; the returned value is a copy
+func (s StaticSystem) DetectSystem(ctx context.Context) (*SystemInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info := *s.Info
+	return &info, nil
+}
+
+// StaticProcesses returns fixed process information
+type StaticProcesses struct {
+	Info *ProcessInfo
+}
+
+// DetectProcesses implements ProcessDetector
+func (s StaticProcesses) DetectProcesses(ctx context.Context) (*ProcessInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.Info == nil {
+		return &ProcessInfo{}, nil
+	}
+	return s.Info, nil
+}
+
+// Detectors gather an Input; only System is required
+type Detectors struct {
+	System      SystemDetector
+	Processes   ProcessDetector
+	Services    ServiceDetector
+	MemoryLimit MemoryLimitDetector
+}
+
+// HostDetectors reads everything from the current host, PHP-FPM processes
+// included
+func HostDetectors() Detectors {
+	return Detectors{
+		System:      HostSystem{},
+		Processes:   HostProcesses{},
+		Services:    HostServices{},
+		MemoryLimit: HostMemoryLimit{},
+	}
+}
+
+// Gather runs all detectors. A missing php binary leaves the memory_limit
+// unknown instead of failing.
+func (d Detectors) Gather(ctx context.Context) (Input, error) {
+	var in Input
+	var err error
+
+	if d.System == nil {
+		return in, errors.New("phptuner: no system detector")
+	}
+	if in.System, err = d.System.DetectSystem(ctx); err != nil {
+		return in, fmt.Errorf("detect system: %w", err)
+	}
+
+	if d.Processes != nil {
+		if in.PHP, err = d.Processes.DetectProcesses(ctx); err != nil {
+			return in, fmt.Errorf("detect processes: %w", err)
+		}
+	}
+
+	if d.Services != nil {
+		if in.Services, err = d.Services.DetectServices(ctx); err != nil {
+			return in, fmt.Errorf("detect services: %w", err)
+		}
+	}
+
+	if d.MemoryLimit != nil {
+		if limit, err := d.MemoryLimit.DetectMemoryLimit(ctx); err == nil {
+			in.MemoryLimitMB = limit
+		} else if ctxErr := ctx.Err(); ctxErr != nil {
+			return in, ctxErr
+		}
+	}
+
+	return in, nil
+}
@@ -0,0 +1,74 @@
+package phptuner_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/muuvmuuv/php-tuner/pkg/phptuner"
+)
+
+// Detect the current host and size its PHP-FPM pool for high traffic
+func ExampleDetectors_Gather() {
+	ctx := context.Background()
+
+	in, err := phptuner.HostDetectors().Gather(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := phptuner.DefaultFPMOptions()
+	opts.TrafficProfile, _ = phptuner.LookupProfile("high", nil)
+	cfg := phptuner.CalculateFPM(in, opts)
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, d := range cfg.Directives() {
+		fmt.Printf("%s = %s\n", d.Name, d.Value)
+	}
+}
+
+// Size a pool for a machine that does not exist yet
+func ExampleCalculateFPM() {
+	opts := phptuner.DefaultFPMOptions()
+	opts.ProcessMemoryMB = 64
+
+	cfg := phptuner.CalculateFPM(phptuner.Input{System: phptuner.Simulate(8, 16384)}, opts)
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, d := range cfg.Directives() {
+		fmt.Printf("%s = %s\n", d.Name, d.Value)
+	}
+	// Output:
+	// pm = dynamic
+	// pm.max_children = 209
+	// pm.process_idle_timeout = 5s
+	// pm.start_servers = 32
+	// pm.min_spare_servers = 16
+	// pm.max_spare_servers = 32
+	// pm.max_requests = 500
+}
+
+// Gather a fixed machine, e.g. one read from a snapshot, without touching
+// the host
+func ExampleStaticSystem() {
+	detectors := phptuner.Detectors{System: phptuner.StaticSystem{Info: phptuner.Simulate(4, 8192)}}
+
+	in, err := detectors.Gather(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := phptuner.DefaultFPMOptions()
+	opts.ProcessMemoryMB = 48
+	cfg := phptuner.CalculateFPM(in, opts)
+
+	fmt.Printf("%d cores, %d MB\n", in.System.CPUCores, in.System.MemTotalMB)
+	fmt.Printf("pm = %s, pm.max_children = %d\n", cfg.PM, cfg.MaxChildren)
+	// Output:
+	// 4 cores, 8192 MB
+	// pm = dynamic, pm.max_children = 134
+}
@@ -0,0 +1,158 @@
+// Package phptuner calculates PHP-FPM, FrankenPHP, Apache mod_php,
+// RoadRunner and Swoole settings from a machine's CPU and memory.
+//
+// Detection and calculation are separate steps. Detectors read the host and
+// accept a context; they are gathered into an Input up front. The Calculate
+// functions are pure and only look at their arguments, so the same Input
+// always yields the same configuration:
+//
+//	in, err := phptuner.HostDetectors().Gather(ctx)
+//	if err != nil {
+//		return err
+//	}
+//
+//	opts := phptuner.DefaultFPMOptions()
+//	opts.TrafficProfile, _ = phptuner.LookupProfile("high", nil)
+//	cfg := phptuner.CalculateFPM(in, opts)
+//
+//	for _, d := range cfg.Directives() {
+//		fmt.Printf("%s = %s\n", d.Name, d.Value)
+//	}
+//
+// Every config has a Validate method that checks the constraints the
+// runtime itself enforces, e.g. PHP-FPM's min_spare_servers <=
+// start_servers <= max_spare_servers <= max_children. The php-tuner
+// commands refuse to print a config that fails it.
+//
+// Traffic profiles are plain values: start from a built-in (low, medium,
+// high, bursty, api, batch, cron) and change its fields, or read custom
+// ones with LoadProfiles.
+//
+// Hypothetical machines need no detection at all:
+//
+//	cfg := phptuner.CalculateFPM(phptuner.Input{System: phptuner.Simulate(8, 16384)}, opts)
+//
+// Most exported types are aliases of the calculator's own types, so the
+// API is frozen by a test instead: every field, method and function
+// signature reachable from this package is recorded in
+// testdata/api.golden, and the test fails on any change until APIVersion
+// is bumped. Added fields raise the minor version, removed or changed ones
+// the major version.
+package phptuner
+
+import (
+	"github.com/muuvmuuv/php-tuner/internal/calculator"
+	"github.com/muuvmuuv/php-tuner/internal/framework"
+	"github.com/muuvmuuv/php-tuner/internal/php"
+	"github.com/muuvmuuv/php-tuner/internal/services"
+	"github.com/muuvmuuv/php-tuner/internal/system"
+)
+
+// APIVersion is the semantic version of this package's API
+const APIVersion = "3.2.0"
+
+// Inputs
+type (
+	Input       = calculator.Input
+	SystemInfo  = system.Info
+	ProcessInfo = php.ProcessInfo
+	Process     = php.Process
+	Service     = services.Service
+)
+
+// Calculation options and results
+type (
+	Profile      = calculator.Profile
+	PMType       = calculator.PMType
+	Directive    = calculator.Directive
+	Decision     = calculator.Decision
+	Trace        = calculator.Trace
+	ReservedItem = calculator.ReservedItem
+
+	FPMOptions = calculator.Options
+	FPMConfig  = calculator.Config
+
+	FrankenPHPOptions = calculator.FrankenPHPOptions
+	FrankenPHPConfig  = calculator.FrankenPHPConfig
+	WorkerSpec        = calculator.WorkerSpec
+	Worker            = calculator.Worker
+
+	ApacheOptions = calculator.ApacheOptions
+	ApacheConfig  = calculator.ApacheConfig
+
+	RoadRunnerOptions = calculator.RoadRunnerOptions
+	RoadRunnerConfig  = calculator.RoadRunnerConfig
+
+	SwooleOptions = calculator.SwooleOptions
+	SwooleConfig  = calculator.SwooleConfig
+
+	// Framework selects the worker integration in FrankenPHPOptions
+	Framework = framework.Framework
+)
+
+const (
+	PMStatic   = calculator.PMStatic
+	PMDynamic  = calculator.PMDynamic
+	PMOnDemand = calculator.PMOnDemand
+
+	FrameworkNone          = framework.None
+	FrameworkLaravelOctane = framework.LaravelOctane
+	FrameworkSymfony       = framework.Symfony
+)
+
+// LookupProfile returns a profile from custom or the built-ins; unknown
+// names are an error
+func LookupProfile(name string, custom map[string]Profile) (Profile, error) {
+	return calculator.LookupProfile(name, custom)
+}
+
+// LoadProfiles reads custom traffic profiles from a JSON file
+func LoadProfiles(path string) (map[string]Profile, error) {
+	return calculator.LoadProfiles(path)
+}
+
+// DefaultFPMOptions returns the options the php-fpm command starts from
+func DefaultFPMOptions() FPMOptions { return calculator.DefaultOptions() }
+
+// DefaultFrankenPHPOptions returns the options the frankenphp command starts from
+func DefaultFrankenPHPOptions() FrankenPHPOptions { return calculator.DefaultFrankenPHPOptions() }
+
+// DefaultApacheOptions returns the options the apache command starts from
+func DefaultApacheOptions() ApacheOptions { return calculator.DefaultApacheOptions() }
+
+// DefaultRoadRunnerOptions returns the options the roadrunner command starts from
+func DefaultRoadRunnerOptions() RoadRunnerOptions { return calculator.DefaultRoadRunnerOptions() }
+
+// DefaultSwooleOptions returns the options the swoole command starts from
+func DefaultSwooleOptions() SwooleOptions { return calculator.DefaultSwooleOptions() }
+
+// CalculateFPM computes a PHP-FPM pool configuration
+func CalculateFPM(in Input, opts FPMOptions) *FPMConfig {
+	return calculator.Calculate(in, opts)
+}
+
+// CalculateFrankenPHP computes the frankenphp global options and workers
+func CalculateFrankenPHP(in Input, opts FrankenPHPOptions) *FrankenPHPConfig {
+	return calculator.CalculateFrankenPHP(in, opts)
+}
+
+// CalculateApache computes prefork MPM settings for mod_php; in.PHP holds
+// the Apache children
+func CalculateApache(in Input, opts ApacheOptions) *ApacheConfig {
+	return calculator.CalculateApache(in, opts)
+}
+
+// CalculateRoadRunner computes the .rr.yaml http.pool settings
+func CalculateRoadRunner(in Input, opts RoadRunnerOptions) *RoadRunnerConfig {
+	return calculator.CalculateRoadRunner(in, opts)
+}
+
+// CalculateSwoole computes Swoole/OpenSwoole server settings
+func CalculateSwoole(in Input, opts SwooleOptions) *SwooleConfig {
+	return calculator.CalculateSwoole(in, opts)
+}
+
+// Simulate returns a machine with the given CPU cores and memory
+func Simulate(cpuCores, memTotalMB int) *SystemInfo {
+	return system.Simulate(cpuCores, memTotalMB)
+}